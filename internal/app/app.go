@@ -7,10 +7,11 @@ import (
 	"blog/internal/infra/minio"
 	"blog/internal/infra/pgsql"
 	"blog/internal/infra/redis"
+	"blog/internal/interfaces/guards"
 	"blog/internal/interfaces/interceptors"
 	"blog/internal/interfaces/middlewares"
-	"blog/internal/router"
 	"os"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -22,37 +23,34 @@ type App struct {
 	Router *gin.Engine
 }
 
-func loadPgsqlConfig(cfg *config.Config) pgsql.Config {
+func loadPgsqlConfig(cfg *config.PgSQLConfig) pgsql.Config {
 	return pgsql.Config{
-		Host:     cfg.PgSQL.Host,
-		Port:     cfg.PgSQL.Port,
-		User:     cfg.PgSQL.User,
-		Password: cfg.PgSQL.Password,
-		DBName:   cfg.PgSQL.DBName,
-		SSLMode:  cfg.PgSQL.SSLMode,
-		MaxIdle:  cfg.PgSQL.MaxIdle,
-		MaxOpen:  cfg.PgSQL.MaxOpen,
-		LogLevel: cfg.PgSQL.LogLevel,
+		Host:     cfg.Host,
+		Port:     cfg.Port,
+		User:     cfg.User,
+		Password: cfg.Password,
+		DBName:   cfg.DBName,
+		SSLMode:  cfg.SSLMode,
+		MaxIdle:  cfg.MaxIdle,
+		MaxOpen:  cfg.MaxOpen,
+		LogLevel: cfg.LogLevel,
 	}
 }
 
 func Setup() (*gnest.GnestApp, error) {
-	app := gnest.New()
-	cfg, err := config.LoadConfig()
+	app, err := gnest.NewFromModule(AppModule)
 	if err != nil {
 		return nil, err
 	}
-	// 1. 提供底层依赖 (注入到容器)
-	pg, err := pgsql.NewPGSQL(loadPgsqlConfig(cfg))
-	if err != nil {
-		return nil, err
-	}
-	app.Provide(pg.DB) // 提供 *gorm.DB
-	router.Setup(app)
+	// 全局增强器需在控制器挂载 (app.Init) 之前注册
 	registerMiddlewares(app)
 	app.GET("/string", func(ctx *gin.Context) string {
 		return "This is a direct string response from Gnest!"
-	})
+	}, gnest.Public())
+	// 接口版本通过 X-API-Version 请求头选择，默认版本与回退策略见配置 versioning
+	app.EnableVersioning(gnest.HeaderVersioning("X-API-Version"))
+	// 接口文档：/openapi.json 与 /docs
+	app.UseOpenAPI(gnest.OpenAPIConfig{Title: "Blog API", Version: "1.0.0"})
 
 	return app, nil
 }
@@ -66,8 +64,21 @@ func registerMiddlewares(app *gnest.GnestApp) {
 	app.Provide(logger.NewLoggerService(env))
 	logInterceptor := &interceptors.LoggingInterceptor{}
 	app.Provide(logInterceptor)
-	app.UseGlobalInterceptors(logInterceptor)
-	// app.Use(middlewares.Response())
+	// 响应信封在日志拦截器内层，日志记录的是最终响应
+	envelope := &gnest.EnvelopeInterceptor{}
+	app.Provide(envelope)
+	// 序列化在信封内层：按 serialize 标签过滤返回的模型字段
+	serializer := &gnest.SerializerInterceptor{}
+	app.Provide(serializer)
+	// 默认请求超时，路由可用 gnest.Timeout 设置更短的时间
+	app.UseGlobalInterceptors(logInterceptor, envelope, serializer, &gnest.TimeoutInterceptor{Timeout: 30 * time.Second})
+	// 限流策略见配置 throttler，路由通过 gnest.Throttle 选择
+	throttler := &gnest.ThrottlerGuard{}
+	app.Provide(throttler)
+	// 鉴权在限流之后：未携带 token 的请求同样计入限流；Public() 标记的路由跳过鉴权
+	auth := &guards.AuthGuard{}
+	app.Provide(auth)
+	app.UseGlobalGuards(throttler, auth)
 	// app.Use(middlewares.Logger(env))
 	app.Use(middlewares.CORS())
 	app.Use(middlewares.Recovery())
@@ -0,0 +1,92 @@
+package app
+
+import (
+	"blog/internal/config"
+	"blog/internal/infra/gnest"
+	"blog/internal/infra/pgsql"
+	"blog/internal/router"
+	"path/filepath"
+
+	"gorm.io/gorm"
+)
+
+// ConfigModule 全局配置模块：config.yaml 叠加 config.<APP_ENV>.yaml、BLOG_ 前缀的环境变量
+// (如 BLOG_PGSQL_HOST) 与 /run/secrets 下的密钥文件，修改配置文件后热更新
+var ConfigModule = gnest.ConfigModule[config.Config](gnest.ConfigOptions{
+	Dir:        filepath.Join("internal", "config"),
+	EnvPrefix:  "BLOG",
+	SecretsDir: "/run/secrets",
+	Watch:      true,
+})
+
+// InfraModule 基础设施模块：导入全局配置模块，数据库连接等以工厂形式声明，启动时按依赖顺序构建
+var InfraModule = &gnest.Module{
+	Name:    "InfraModule",
+	Global:  true,
+	Imports: []*gnest.Module{ConfigModule},
+	Providers: []interface{}{
+		gnest.Factory(newPGSQL),
+		gnest.Factory(func(pg *pgsql.PGSQL) *gorm.DB { return pg.DB }), // 提供 *gorm.DB
+		gnest.Factory(newLifecycleOptions),
+		gnest.Factory(newEnvelopeOptions),
+		gnest.Factory(newVersioningOptions),
+		gnest.Factory(newThrottlerOptions),
+	},
+	Exports: []interface{}{(*pgsql.PGSQL)(nil), (*gorm.DB)(nil), (*gnest.LifecycleOptions)(nil), (*gnest.EnvelopeOptions)(nil), (*gnest.VersioningOptions)(nil), (*gnest.ThrottlerOptions)(nil)},
+}
+
+// AppModule 根模块：导入基础设施、定时任务调度、响应缓存与所有 HTTP 模块
+var AppModule = &gnest.Module{
+	Name:    "AppModule",
+	Imports: append([]*gnest.Module{InfraModule, gnest.ScheduleModule(gnest.ScheduleOptions{}), gnest.CacheModule(gnest.CacheOptions{})}, router.Modules...),
+}
+
+// newLifecycleOptions 生命周期钩子的超时取自配置
+func newLifecycleOptions(cfg *config.Config) *gnest.LifecycleOptions {
+	return &gnest.LifecycleOptions{
+		HookTimeout:     cfg.Lifecycle.HookTimeout,
+		ShutdownTimeout: cfg.Lifecycle.ShutdownTimeout,
+		Hooks:           cfg.Lifecycle.Hooks,
+	}
+}
+
+// newEnvelopeOptions 响应信封的字段名取自 middlewaresKeys.response
+func newEnvelopeOptions(cfg *config.Config) *gnest.EnvelopeOptions {
+	keys := cfg.MiddlewaresKeys.Response
+	return &gnest.EnvelopeOptions{
+		CodeKey:    keys.StatusCode,
+		MessageKey: keys.Message,
+		DataKey:    keys.Response,
+	}
+}
+
+// newVersioningOptions 接口版本的默认版本、回退策略与弃用信息取自配置
+func newVersioningOptions(cfg *config.Config) *gnest.VersioningOptions {
+	opts := &gnest.VersioningOptions{
+		DefaultVersion: cfg.Versioning.DefaultVersion,
+		Fallback:       gnest.VersionFallback(cfg.Versioning.Fallback),
+		Deprecated:     make(map[string]gnest.VersionDeprecation),
+	}
+	for ver, d := range cfg.Versioning.Deprecated {
+		opts.Deprecated[ver] = gnest.VersionDeprecation{Since: d.Since, Sunset: d.Sunset, Link: d.Link}
+	}
+	return opts
+}
+
+// newThrottlerOptions 限流策略取自配置 throttler.policies
+func newThrottlerOptions(cfg *config.Config) *gnest.ThrottlerOptions {
+	opts := &gnest.ThrottlerOptions{Policies: make(map[string]gnest.ThrottlePolicy)}
+	for name, p := range cfg.Throttler.Policies {
+		opts.Policies[name] = gnest.ThrottlePolicy{
+			Limit:     p.Limit,
+			Window:    p.Window,
+			Algorithm: gnest.ThrottleAlgorithm(p.Algorithm),
+			By:        p.By,
+		}
+	}
+	return opts
+}
+
+func newPGSQL(cfg *config.PgSQLConfig) (*pgsql.PGSQL, error) {
+	return pgsql.NewPGSQL(loadPgsqlConfig(cfg))
+}
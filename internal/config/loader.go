@@ -1,77 +1,93 @@
 package config
 
-import (
-	"fmt"
-	"os"
-	"path/filepath"
-
-	"github.com/spf13/viper"
-)
+import "time"
 
+// Config 应用配置，由 app.ConfigModule 加载并以 *gnest.ConfigService[Config] 注入；
+// 需要热更新的值 (如 SecretKey) 通过 Get() 读取
 type Config struct {
-	SecretKey string
+	SecretKey string `validate:"required"`
 	ConfigKey string
 
-	// 数据库
-	PgSQL struct {
-		Host     string
-		Port     int
-		User     string
-		Password string
-		DBName   string
-		SSLMode  string
-		MaxIdle  int
-		MaxOpen  int
-		LogLevel string // 或 logger.LogLevel
-	}
+	PgSQL           PgSQLConfig
+	Redis           RedisConfig
+	Minio           MinioConfig
+	Lifecycle       LifecycleConfig
+	Versioning      VersioningConfig
+	Throttler       ThrottlerConfig
+	MiddlewaresKeys MiddlewaresKeysConfig
+}
 
-	// Redis
-	Redis struct {
-		Addr     string
-		Password string
-		DB       int
-	}
+// PgSQLConfig 数据库
+type PgSQLConfig struct {
+	Host     string `validate:"required"`
+	Port     int    `validate:"required,min=1,max=65535"`
+	User     string `validate:"required"`
+	Password string
+	DBName   string `validate:"required"`
+	SSLMode  string
+	MaxIdle  int    `validate:"min=0"`
+	MaxOpen  int    `validate:"min=0"`
+	LogLevel string // 或 logger.LogLevel
+}
 
-	// MinIO
-	Minio struct {
-		Endpoint        string
-		AccessKeyID     string
-		SecretAccessKey string
-		UseSSL          bool
-		Bucket          string
-	}
+// RedisConfig Redis
+type RedisConfig struct {
+	Addr     string
+	Password string
+	DB       int `validate:"min=0"`
+}
 
-	// 中间件 Key 配置
-	MiddlewaresKeys struct {
-		Response struct {
-			Response   string
-			StatusCode string
-			Message    string
-		}
-		Validate struct {
-			Validated string
-		}
-		Auth struct {
-			TokenKey string
-		}
-	}
+// MinioConfig MinIO
+type MinioConfig struct {
+	Endpoint        string
+	AccessKeyID     string
+	SecretAccessKey string
+	UseSSL          bool
+	Bucket          string
 }
 
-func LoadConfig() (*Config, error) {
-	// 获取程序当前的工作目录
-	currentDir, err := os.Getwd()
-	if err != nil {
-		return nil, fmt.Errorf("failed to get current directory: %s", err)
+// LifecycleConfig 生命周期钩子超时
+type LifecycleConfig struct {
+	HookTimeout     time.Duration            `validate:"min=0"`
+	ShutdownTimeout time.Duration            `validate:"min=0"`
+	Hooks           map[string]time.Duration // 按钩子名覆盖，如 onModuleInit: 30s
+}
+
+// VersioningConfig 接口版本
+type VersioningConfig struct {
+	DefaultVersion string
+	Fallback       string `validate:"omitempty,oneof=none default latest"`
+	Deprecated     map[string]struct {
+		Since  time.Time // YAML 时间戳，如 2026-01-01
+		Sunset time.Time
+		Link   string
 	}
-	configFilePath := filepath.Join(currentDir, "internal", "config", "config.yaml")
-	viper.SetConfigFile(configFilePath)
+}
+
+// ThrottlerConfig 限流策略，default 作用于未声明策略的路由
+type ThrottlerConfig struct {
+	Policies map[string]ThrottlePolicyConfig `validate:"dive"`
+}
+
+// ThrottlePolicyConfig 每 window 最多 limit 次，by 为限流键的组成，如 [ip, body:userName]
+type ThrottlePolicyConfig struct {
+	Limit     int           `validate:"gt=0"`
+	Window    time.Duration `validate:"gt=0"`
+	Algorithm string        `validate:"omitempty,oneof=fixed-window sliding-window token-bucket"`
+	By        []string
+}
 
-	if err := viper.ReadInConfig(); err != nil {
-		return nil, err
+// MiddlewaresKeysConfig 中间件 Key 配置
+type MiddlewaresKeysConfig struct {
+	Response struct {
+		Response   string
+		StatusCode string
+		Message    string
+	}
+	Validate struct {
+		Validated string
 	}
-	cfg := Config{}
-	if err := viper.Unmarshal(&cfg); err != nil {
-		return nil, err
+	Auth struct {
+		TokenKey string
 	}
-	return &cfg, nil
 }
@@ -0,0 +1,13 @@
+package user
+
+import "time"
+
+// UserRegisteredEvent 用户注册成功后发布，监听方法名为 OnUserRegistered
+type UserRegisteredEvent struct {
+	UserID       string
+	UserName     string
+	Email        string
+	RegisteredAt time.Time
+}
+
+func (UserRegisteredEvent) EventName() string { return "user.registered" }
@@ -7,23 +7,25 @@ import (
 	"gorm.io/gorm"
 )
 
+// User 用户模型，作为响应返回时按 serialize 标签过滤：密码、盐与 refreshToken 不输出
+// (json 标签同样为 "-"，未经序列化拦截器直接编码时也不会泄露)，联系方式等个人信息仅 admin / self 分组可见
 type User struct {
 	ID           string           `gorm:"primaryKey" json:"id"`
 	UserName     string           `gorm:"not null" json:"userName"`
-	Password     string           `gorm:"not null" json:"password"`
-	Email        string           `gorm:"" json:"email"`
-	Phone        string           `gorm:"" json:"phone"`
+	Password     string           `gorm:"not null" json:"-" serialize:"-"`
+	Email        string           `gorm:"" json:"email" serialize:"groups=admin,self"`
+	Phone        string           `gorm:"" json:"phone" serialize:"groups=admin,self"`
 	FullName     string           `gorm:"" json:"fullName"`
 	Avatar       string           `gorm:"" json:"avatar"`
 	Role         constants.Role   `gorm:"not null" json:"role"`
 	Status       constants.Status `gorm:"not null" json:"status"`
 	Gender       string           `gorm:"" json:"gender"`
-	Birthday     time.Time        `gorm:"" json:"birthday"`
-	Address      string           `gorm:"" json:"address"`
-	LastLoginAt  time.Time        `gorm:"" json:"lastLoginAt"`
+	Birthday     time.Time        `gorm:"" json:"birthday" serialize:"groups=admin,self;format=2006-01-02"`
+	Address      string           `gorm:"" json:"address" serialize:"groups=admin,self"`
+	LastLoginAt  time.Time        `gorm:"" json:"lastLoginAt" serialize:"groups=admin,self"`
 	CreatedAt    time.Time        `gorm:"autoCreateTime" json:"createAt"`
 	UpdatedAt    time.Time        `gorm:"autoUpdateTime" json:"updateAt"`
-	Salt         string           `gorm:"not null" json:"salt"`
-	RefreshToken string           `gorm:"" json:"refreshToken"`
-	DeletedAt    gorm.DeletedAt   `gorm:"index" json:"deletedAt"`
+	Salt         string           `gorm:"not null" json:"-" serialize:"-"`
+	RefreshToken string           `gorm:"" json:"-" serialize:"-"`
+	DeletedAt    gorm.DeletedAt   `gorm:"index" json:"deletedAt" serialize:"groups=admin"`
 }
@@ -0,0 +1,10 @@
+package user
+
+import "blog/internal/infra/gnest"
+
+// Module 用户领域模块：仓储以 Repository 接口留在模块内部，只对外导出 UserService
+var Module = &gnest.Module{
+	Name:      "UserModule",
+	Providers: []interface{}{gnest.As[Repository](&UserRepository{}), &UserService{}},
+	Exports:   []interface{}{(*UserService)(nil)},
+}
@@ -1,36 +1,49 @@
 package user
 
 import (
+	"context"
+
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
+// Repository 用户仓储，UserService 依赖该接口，测试中可替换为内存实现
+type Repository interface {
+	Create(ctx context.Context, user *User) error
+	FindByUserName(ctx context.Context, userName string) (*User, error)
+	Update(ctx context.Context, userId string, userInfo *User) error
+	Delete(ctx context.Context, user *User) error
+}
+
+// UserRepository 基于 gorm 的 Repository 实现
 type UserRepository struct {
 	// gnest 会自动注入在 app.Provide 注册过的 *gorm.DB
 	DB *gorm.DB
 }
 
-func (r *UserRepository) Create(user *User) error {
+// 所有方法接收请求的 ctx，客户端断开或请求超时时取消正在执行的查询
+func (r *UserRepository) Create(ctx context.Context, user *User) error {
 	user.ID = uuid.NewString()
-	return r.DB.Create(&user).Error
+	return r.DB.WithContext(ctx).Create(&user).Error
 }
 
-func (r *UserRepository) FindByUserName(userName string) (*User, error) {
+func (r *UserRepository) FindByUserName(ctx context.Context, userName string) (*User, error) {
 	var user User
-	if err := r.DB.Where("user_name = ?", userName).First(&user).Error; err != nil {
+	if err := r.DB.WithContext(ctx).Where("user_name = ?", userName).First(&user).Error; err != nil {
 		return nil, err
 	}
 	return &user, nil
 }
 
-func (r *UserRepository) Update(userId string, userInfo *User) error {
+func (r *UserRepository) Update(ctx context.Context, userId string, userInfo *User) error {
+	db := r.DB.WithContext(ctx)
 	var user User
-	if err := r.DB.First(&user, userId).Error; err != nil {
+	if err := db.First(&user, userId).Error; err != nil {
 		return err
 	}
-	return r.DB.Model(&user).Updates(&userInfo).Error
+	return db.Model(&user).Updates(&userInfo).Error
 }
 
-func (r *UserRepository) Delete(user *User) error {
-	return r.DB.Delete(&user).Error
+func (r *UserRepository) Delete(ctx context.Context, user *User) error {
+	return r.DB.WithContext(ctx).Delete(&user).Error
 }
@@ -3,6 +3,8 @@ package user
 import (
 	"blog/internal/common/constants"
 	"blog/internal/config"
+	"blog/internal/infra/gnest"
+	"context"
 	errors "errors"
 	"time"
 
@@ -17,19 +19,21 @@ import (
 )
 
 type UserService struct {
-	Repo *UserRepository
+	Repo   Repository
+	Events *gnest.EventBus
+	Config *gnest.ConfigService[config.Config]
 }
 
-func NewUserService(userRepository *UserRepository) *UserService {
+func NewUserService(userRepository Repository) *UserService {
 	return &UserService{
 		Repo: userRepository,
 	}
 }
 
-func (s *UserService) Register(userInfo *CreateUserDTO) (*User, error) {
-	findUser, err := s.Repo.FindByUserName(userInfo.UserName)
+func (s *UserService) Register(ctx context.Context, userInfo *CreateUserDTO) (*User, error) {
+	findUser, err := s.Repo.FindByUserName(ctx, userInfo.UserName)
 	if findUser != nil {
-		return nil, errors.New("this username has already been registered")
+		return nil, gnest.Conflict("this username has already been registered").WithCode("USERNAME_TAKEN")
 	}
 
 	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
@@ -56,60 +60,72 @@ func (s *UserService) Register(userInfo *CreateUserDTO) (*User, error) {
 		CreatedAt: time.Now(),
 	}
 
-	return user, s.Repo.Create(user)
+	if err := s.Repo.Create(ctx, user); err != nil {
+		return nil, err
+	}
+	gnest.Publish(ctx, s.Events, UserRegisteredEvent{
+		UserID:       user.ID,
+		UserName:     user.UserName,
+		Email:        user.Email,
+		RegisteredAt: user.CreatedAt,
+	})
+	return user, nil
 }
 
-func (s *UserService) Authenticate(userName string, password string) (*User, string, string, error) {
-	user, err := s.Repo.FindByUserName(userName)
-	if user == nil {
-		return nil, "", "", errors.New("the current username is not registered")
+func (s *UserService) Authenticate(ctx context.Context, userName string, password string) (*User, string, string, error) {
+	user, err := s.Repo.FindByUserName(ctx, userName)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, "", "", gnest.Unauthorized("the current username is not registered").WithCode("USER_NOT_REGISTERED")
 	}
 	if err != nil {
 		return nil, "", "", err
 	}
 	if !verifyPassword(password, user.Password, user.Salt) {
-		return nil, "", "", errors.New("password error")
+		return nil, "", "", gnest.Unauthorized("password error").WithCode("PASSWORD_INCORRECT")
 	}
 
-	accessToken, err := generateAccessToken(user.UserName, user.Password)
+	secret := s.secretKey()
+	accessToken, err := generateAccessToken(secret, user.UserName, user.Password)
 	if err != nil {
 		return nil, "", "", err
 	}
 
-	refreshToken, err := generateRefreshToken(user.UserName, user.Password)
+	refreshToken, err := generateRefreshToken(secret, user.UserName, user.Password)
 	if err != nil {
 		return nil, "", "", err
 	}
 	return user, accessToken, refreshToken, nil
 }
 
-func (s *UserService) RefreshToken(refreshToken string) (string, error) {
-	cfg, err := config.LoadConfig()
-	if err != nil {
-		return "", err
-	}
+func (s *UserService) RefreshToken(ctx context.Context, refreshToken string) (string, error) {
+	secret := s.secretKey()
 	token, err := jwt.ParseWithClaims(refreshToken, &jwt.StandardClaims{}, func(token *jwt.Token) (interface{}, error) {
-		return []byte(cfg.SecretKey), nil
+		return []byte(secret), nil
 	})
 	if err != nil {
-		return "", err
+		return "", gnest.Unauthorized("refreshToken is invalid").WithCode("REFRESH_TOKEN_INVALID").WithCause(err)
 	}
 	if !token.Valid {
-		return "", errors.New("refreshToken is invalid")
+		return "", gnest.Unauthorized("refreshToken is invalid").WithCode("REFRESH_TOKEN_INVALID")
 	}
 	claims := token.Claims.(*jwt.StandardClaims)
 	userName := claims.Subject
-	user, err := s.Repo.FindByUserName(userName)
+	user, err := s.Repo.FindByUserName(ctx, userName)
 	if err != nil {
 		return "", err
 	}
-	accessToken, err := generateAccessToken(user.UserName, user.Password)
+	accessToken, err := generateAccessToken(secret, user.UserName, user.Password)
 	if err != nil {
 		return "", err
 	}
 	return accessToken, nil
 }
 
+// secretKey 每次读取当前配置，热更新后立即生效
+func (s *UserService) secretKey() string {
+	return s.Config.Get().SecretKey
+}
+
 func generateSalt() string {
 	salt := make([]byte, 16)
 	_, err := rand.Read(salt)
@@ -131,7 +147,7 @@ func verifyPassword(inputPassword, hashedPassword, salt string) bool {
 	return err == nil
 }
 
-func generateToken(userName string, hashedPassword string, duration time.Duration) (string, error) {
+func generateToken(secret, userName string, hashedPassword string, duration time.Duration) (string, error) {
 	// 设置 token 的过期时间为 7 天
 	expirationTime := time.Now().Add(duration)
 	claims := &jwt.StandardClaims{
@@ -139,23 +155,19 @@ func generateToken(userName string, hashedPassword string, duration time.Duratio
 		Subject:   userName + hashedPassword,
 	}
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	cfg, err := config.LoadConfig()
-	if err != nil {
-		panic(err.Error())
-	}
-	return token.SignedString([]byte(cfg.SecretKey))
+	return token.SignedString([]byte(secret))
 }
 
-func generateAccessToken(username string, pwd string) (string, error) {
-	accessToken, err := generateToken(username, pwd, 15*time.Minute)
+func generateAccessToken(secret, username string, pwd string) (string, error) {
+	accessToken, err := generateToken(secret, username, pwd, 15*time.Minute)
 	if err != nil {
 		return "", err
 	}
 	return accessToken, err
 }
 
-func generateRefreshToken(username string, pwd string) (string, error) {
-	refreshToken, err := generateToken(username, pwd, 7*24*time.Hour)
+func generateRefreshToken(secret, username string, pwd string) (string, error) {
+	refreshToken, err := generateToken(secret, username, pwd, 7*24*time.Hour)
 	if err != nil {
 		return "", err
 	}
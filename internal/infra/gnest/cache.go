@@ -0,0 +1,372 @@
+package gnest
+
+import (
+	"bytes"
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ==========================================
+// 响应缓存 (Caching)
+// ==========================================
+
+// CacheInterceptor 缓存 GET 请求的响应，可注册为全局、路由组或路由级拦截器：
+//
+//	Imports: []*gnest.Module{gnest.CacheModule(gnest.CacheOptions{Store: redis.NewCacheStore(redisClient)})}
+//
+//	type PostController struct {
+//		Cache *gnest.CacheInterceptor
+//	}
+//	rg.GET("/posts/:id", ctrl.Get, ctrl.Cache, gnest.CacheTTL(5*time.Minute), gnest.CacheTags("post:{id}"))
+//
+//	// 文章修改后失效相关缓存
+//	cache.InvalidateTags("post:42")
+//
+// 缓存的是最终写出的响应 (外层序列化、信封拦截器与内容协商之后的内容类型与响应体)，
+// serialize:"-" 等被过滤的字段不会进入存储；命中时原样写出，不再经过处理器与序列化。
+// 缓存键由方法、路径、排序后的查询参数、Accept 与命中的接口版本组成，CachePerUser 时追加用户标识；
+// 序列化分组随调用方变化 (SerializerOptions.Groups) 的路由应同时声明 CachePerUser。
+// 请求携带 Cache-Control: no-cache 时跳过读取并刷新缓存，no-store 时完全绕过
+
+// 缓存相关的元数据键
+const (
+	CacheTTLKey     = "cacheTTL"
+	CacheTagsKey    = "cacheTags"
+	CachePerUserKey = "cachePerUser"
+	NoCacheKey      = "noCache"
+)
+
+// CacheTTL 覆盖路由 (组) 的缓存时间
+func CacheTTL(ttl time.Duration) Metadata {
+	return SetMetadata(CacheTTLKey, ttl)
+}
+
+// CacheTags 为缓存条目打标签，{name} 替换为同名路径参数，如 "post:{id}"
+func CacheTags(tags ...string) Metadata {
+	return SetMetadata(CacheTagsKey, tags)
+}
+
+// CachePerUser 按用户分别缓存，用户标识由 CacheOptions.UserKey 决定
+func CachePerUser() Metadata {
+	return SetMetadata(CachePerUserKey, true)
+}
+
+// NoCache 在全局或组级缓存下排除路由
+func NoCache() Metadata {
+	return SetMetadata(NoCacheKey, true)
+}
+
+// CacheStore 缓存存储，值为编码后的响应 (内容类型与响应体)；tags 为带前缀的标签键
+type CacheStore interface {
+	Get(ctx context.Context, key string) (value []byte, ok bool, err error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration, tags []string) error
+	InvalidateTags(ctx context.Context, tags ...string) error
+}
+
+// CacheOptions 缓存配置
+type CacheOptions struct {
+	Store   CacheStore                  // 默认容量 1000 的内存 LRU
+	TTL     time.Duration               // 默认缓存时间，默认 1 分钟
+	Prefix  string                      // 键前缀，默认 "gnest:cache:"
+	UserKey func(c *gin.Context) string // CachePerUser 的用户标识，默认取 Authorization 请求头的摘要
+}
+
+// Cache 缓存服务，由 CacheModule 导出，可注入后主动失效缓存
+type Cache struct {
+	opts CacheOptions
+}
+
+// cachedResponse 存储中的一条响应
+type cachedResponse struct {
+	ContentType string `json:"contentType"`
+	Body        []byte `json:"body"`
+}
+
+// NewCache 创建缓存服务
+func NewCache(opts CacheOptions) *Cache {
+	if opts.Store == nil {
+		opts.Store = NewMemoryCacheStore(0)
+	}
+	if opts.TTL <= 0 {
+		opts.TTL = time.Minute
+	}
+	if opts.Prefix == "" {
+		opts.Prefix = "gnest:cache:"
+	}
+	if opts.UserKey == nil {
+		opts.UserKey = authorizationDigest
+	}
+	return &Cache{opts: opts}
+}
+
+// CacheModule 创建全局的缓存模块，导出 *Cache 与 *CacheInterceptor
+func CacheModule(opts CacheOptions) *Module {
+	cache := NewCache(opts)
+	return &Module{
+		Name:      "CacheModule",
+		Global:    true,
+		Providers: []interface{}{cache, &CacheInterceptor{Cache: cache}},
+		Exports:   []interface{}{(*Cache)(nil), (*CacheInterceptor)(nil)},
+	}
+}
+
+// InvalidateTags 删除带有任一标签的缓存条目
+func (c *Cache) InvalidateTags(tags ...string) error {
+	if len(tags) == 0 {
+		return nil
+	}
+	return c.opts.Store.InvalidateTags(context.Background(), c.tagKeys(tags)...)
+}
+
+func (c *Cache) tagKeys(tags []string) []string {
+	keys := make([]string, len(tags))
+	for i, tag := range tags {
+		keys[i] = c.opts.Prefix + "tag:" + tag
+	}
+	return keys
+}
+
+// key 方法 + 路径 + 排序后的查询参数 + Accept [+ 版本] [+ 用户]
+func (c *Cache) key(ctx *gin.Context) string {
+	var b strings.Builder
+	b.WriteString(c.opts.Prefix)
+	b.WriteString(ctx.Request.Method)
+	b.WriteByte(' ')
+	b.WriteString(ctx.Request.URL.Path)
+	if q := ctx.Request.URL.Query(); len(q) > 0 {
+		b.WriteByte('?')
+		b.WriteString(q.Encode())
+	}
+	if accept := ctx.GetHeader("Accept"); accept != "" {
+		b.WriteString("|a=")
+		b.WriteString(accept)
+	}
+	if v := VersionOf(ctx); v != "" {
+		b.WriteString("|v=")
+		b.WriteString(v)
+	}
+	if perUser, _ := GetMetadata[bool](ctx, CachePerUserKey); perUser {
+		b.WriteString("|u=")
+		b.WriteString(c.opts.UserKey(ctx))
+	}
+	return b.String()
+}
+
+// cacheWriter 记录写出的响应体，响应结束后写入缓存
+type cacheWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *cacheWriter) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *cacheWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// CacheInterceptor 缓存拦截器，Cache 由 CacheModule 注入
+type CacheInterceptor struct {
+	Cache *Cache
+}
+
+func (i *CacheInterceptor) Intercept(c *gin.Context, next func() interface{}) interface{} {
+	if i.Cache == nil || c.Request.Method != http.MethodGet {
+		return next()
+	}
+	if skip, _ := GetMetadata[bool](c, NoCacheKey); skip {
+		return next()
+	}
+	control := c.GetHeader("Cache-Control")
+	if hasCacheDirective(control, "no-store") {
+		return next()
+	}
+
+	cache, ctx := i.Cache, c.Request.Context()
+	key := cache.key(c)
+	if !hasCacheDirective(control, "no-cache") {
+		data, ok, err := cache.opts.Store.Get(ctx, key)
+		if err != nil {
+			log.Printf("[Gnest] cache get %s: %v", key, err)
+		} else if ok {
+			var entry cachedResponse
+			if err := json.Unmarshal(data, &entry); err == nil && entry.ContentType != "" {
+				c.Header("X-Cache", "HIT")
+				c.Writer.Header().Add("Vary", "Accept")
+				return DataResult{ContentType: entry.ContentType, Data: entry.Body}
+			}
+		}
+	}
+
+	res := next()
+	if c.Writer.Written() || !isPlainResult(res) {
+		return res
+	}
+	ttl := cache.opts.TTL
+	if d, ok := GetMetadata[time.Duration](c, CacheTTLKey); ok && d > 0 {
+		ttl = d
+	}
+	tags := cache.tagKeys(routeCacheTags(c))
+	// 返回值还要经过外层拦截器与内容协商，记录实际写出的响应而不是 res
+	w := &cacheWriter{ResponseWriter: c.Writer}
+	c.Writer = w
+	afterResponse(c, func() {
+		if w.Status() != http.StatusOK {
+			return
+		}
+		data, err := json.Marshal(cachedResponse{ContentType: w.Header().Get("Content-Type"), Body: w.body.Bytes()})
+		if err != nil {
+			return
+		}
+		// 外层拦截器 (如 TimeoutInterceptor) 返回时已取消各自的 ctx，写入缓存不受其影响
+		sctx := context.WithoutCancel(c.Request.Context())
+		if err := cache.opts.Store.Set(sctx, key, data, ttl, tags); err != nil {
+			log.Printf("[Gnest] cache set %s: %v", key, err)
+		}
+	})
+	c.Header("X-Cache", "MISS")
+	return res
+}
+
+// routeCacheTags 展开 CacheTags 中的路径参数
+func routeCacheTags(c *gin.Context) []string {
+	tags, _ := GetMetadata[[]string](c, CacheTagsKey)
+	out := make([]string, len(tags))
+	for i, tag := range tags {
+		for _, p := range c.Params {
+			tag = strings.ReplaceAll(tag, "{"+p.Key+"}", p.Value)
+		}
+		out[i] = tag
+	}
+	return out
+}
+
+func hasCacheDirective(header, directive string) bool {
+	for _, d := range strings.Split(header, ",") {
+		if strings.EqualFold(strings.TrimSpace(d), directive) {
+			return true
+		}
+	}
+	return false
+}
+
+// authorizationDigest 以 Authorization 请求头的摘要作为用户标识，避免凭证出现在缓存键中
+func authorizationDigest(c *gin.Context) string {
+	auth := c.GetHeader("Authorization")
+	if auth == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(auth))
+	return hex.EncodeToString(sum[:8])
+}
+
+// ==========================================
+// 内存 LRU 存储 (Memory Store)
+// ==========================================
+
+// MemoryCacheStore 进程内的 LRU 缓存，超出容量时淘汰最久未使用的条目
+type MemoryCacheStore struct {
+	mu       sync.Mutex
+	capacity int
+	ll       *list.List
+	items    map[string]*list.Element
+	tags     map[string]map[string]struct{} // 标签键 -> 缓存键
+}
+
+type memoryCacheEntry struct {
+	key     string
+	value   []byte
+	expires time.Time // 零值表示不过期
+	tags    []string
+}
+
+// NewMemoryCacheStore 创建内存存储，capacity <= 0 时为 1000
+func NewMemoryCacheStore(capacity int) *MemoryCacheStore {
+	if capacity <= 0 {
+		capacity = 1000
+	}
+	return &MemoryCacheStore{
+		capacity: capacity,
+		ll:       list.New(),
+		items:    make(map[string]*list.Element),
+		tags:     make(map[string]map[string]struct{}),
+	}
+}
+
+func (s *MemoryCacheStore) Get(_ context.Context, key string) ([]byte, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	el, ok := s.items[key]
+	if !ok {
+		return nil, false, nil
+	}
+	e := el.Value.(*memoryCacheEntry)
+	if !e.expires.IsZero() && time.Now().After(e.expires) {
+		s.remove(el)
+		return nil, false, nil
+	}
+	s.ll.MoveToFront(el)
+	return e.value, true, nil
+}
+
+func (s *MemoryCacheStore) Set(_ context.Context, key string, value []byte, ttl time.Duration, tags []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if el, ok := s.items[key]; ok {
+		s.remove(el)
+	}
+	e := &memoryCacheEntry{key: key, value: value, tags: tags}
+	if ttl > 0 {
+		e.expires = time.Now().Add(ttl)
+	}
+	s.items[key] = s.ll.PushFront(e)
+	for _, tag := range tags {
+		if s.tags[tag] == nil {
+			s.tags[tag] = make(map[string]struct{})
+		}
+		s.tags[tag][key] = struct{}{}
+	}
+	for s.ll.Len() > s.capacity {
+		s.remove(s.ll.Back())
+	}
+	return nil
+}
+
+func (s *MemoryCacheStore) InvalidateTags(_ context.Context, tags ...string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, tag := range tags {
+		for key := range s.tags[tag] {
+			if el, ok := s.items[key]; ok {
+				s.remove(el)
+			}
+		}
+		delete(s.tags, tag)
+	}
+	return nil
+}
+
+// remove 删除条目并清理标签索引，调用方持有锁
+func (s *MemoryCacheStore) remove(el *list.Element) {
+	e := s.ll.Remove(el).(*memoryCacheEntry)
+	delete(s.items, e.key)
+	for _, tag := range e.tags {
+		delete(s.tags[tag], e.key)
+		if len(s.tags[tag]) == 0 {
+			delete(s.tags, tag)
+		}
+	}
+}
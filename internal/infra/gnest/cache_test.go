@@ -0,0 +1,88 @@
+package gnest_test
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"blog/internal/infra/gnest"
+)
+
+type account struct {
+	Name     string `json:"name"`
+	Password string `json:"password" serialize:"-"`
+}
+
+func TestCacheStoresSerializedResponse(t *testing.T) {
+	store := gnest.NewMemoryCacheStore(0)
+	cache := &gnest.CacheInterceptor{Cache: gnest.NewCache(gnest.CacheOptions{Store: store})}
+	calls := 0
+	app := gnest.NewTestingApp()
+	app.UseGlobalInterceptors(&gnest.EnvelopeInterceptor{}, &gnest.SerializerInterceptor{})
+	app.GET("/accounts/me", func() account {
+		calls++
+		return account{Name: "alice", Password: "secret"}
+	}, cache)
+	if err := app.Compile(); err != nil {
+		t.Fatal(err)
+	}
+
+	miss := app.Client(t).GET("/accounts/me").ExpectStatus(http.StatusOK).ExpectHeader("X-Cache", "MISS").Response()
+	hit := app.Client(t).GET("/accounts/me").ExpectStatus(http.StatusOK).ExpectHeader("X-Cache", "HIT").Response()
+	if calls != 1 {
+		t.Errorf("expected the handler to run once, got %d", calls)
+	}
+	if miss.Body.String() != hit.Body.String() || hit.Header().Get("Content-Type") != miss.Header().Get("Content-Type") {
+		t.Errorf("expected the hit to match the miss:\n%s %s\n%s %s",
+			miss.Header().Get("Content-Type"), miss.Body, hit.Header().Get("Content-Type"), hit.Body)
+	}
+	if !strings.Contains(hit.Body.String(), `"data":{"name":"alice"}`) {
+		t.Errorf("expected the enveloped and serialized body, got %s", hit.Body)
+	}
+
+	data, ok, err := store.Get(context.Background(), "gnest:cache:GET /accounts/me")
+	if err != nil || !ok {
+		t.Fatalf("expected a cache entry, got ok=%v err=%v", ok, err)
+	}
+	if strings.Contains(string(data), "secret") || strings.Contains(string(data), "c2VjcmV0") {
+		t.Errorf("excluded fields must not reach the store: %s", data)
+	}
+
+	xml := app.Client(t).GET("/accounts/me").Header("Accept", "application/xml").ExpectHeader("X-Cache", "MISS").Response()
+	if !strings.HasPrefix(xml.Header().Get("Content-Type"), "application/xml") {
+		t.Errorf("expected a separate XML entry, got %s", xml.Header().Get("Content-Type"))
+	}
+}
+
+// ctxCheckingStore 拒绝在已取消的 ctx 上写入，模拟 redis 等按 ctx 中止请求的存储
+type ctxCheckingStore struct {
+	gnest.CacheStore
+	sets int
+}
+
+func (s *ctxCheckingStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration, tags []string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.sets++
+	return s.CacheStore.Set(ctx, key, value, ttl, tags)
+}
+
+func TestCacheWritesUnderGlobalTimeout(t *testing.T) {
+	store := &ctxCheckingStore{CacheStore: gnest.NewMemoryCacheStore(0)}
+	cache := &gnest.CacheInterceptor{Cache: gnest.NewCache(gnest.CacheOptions{Store: store})}
+	app := gnest.NewTestingApp()
+	app.UseGlobalInterceptors(&gnest.TimeoutInterceptor{Timeout: time.Minute})
+	app.GET("/ping", func() map[string]string { return map[string]string{"pong": "1"} }, cache)
+	if err := app.Compile(); err != nil {
+		t.Fatal(err)
+	}
+
+	app.Client(t).GET("/ping").ExpectStatus(http.StatusOK).ExpectHeader("X-Cache", "MISS")
+	if store.sets != 1 {
+		t.Fatalf("expected the response to be stored with a live ctx, got %d writes", store.sets)
+	}
+	app.Client(t).GET("/ping").ExpectStatus(http.StatusOK).ExpectHeader("X-Cache", "HIT")
+}
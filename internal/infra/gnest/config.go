@@ -0,0 +1,343 @@
+package gnest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/go-playground/validator/v10"
+	"github.com/spf13/viper"
+)
+
+// ==========================================
+// 配置服务 (ConfigService)
+// ==========================================
+
+// ConfigModule 加载一次配置并按以下顺序叠加 (后者优先)：
+//
+//	<Dir>/<Name>.yaml  ->  <Dir>/<Name>.<Env>.yaml  ->  环境变量 (BLOG_PGSQL_HOST)  ->  密钥文件
+//
+// 加载后按 validate 标签校验，失败时中止启动。模块导出 *ConfigService[T]、*T 以及 T 中
+// 每个具名结构体字段的指针 (如 *config.PgSQLConfig)，可直接注入：
+//
+//	Imports: []*gnest.Module{gnest.ConfigModule[config.Config](gnest.ConfigOptions{EnvPrefix: "BLOG"})}
+//	func newPGSQL(cfg *config.PgSQLConfig) (*pgsql.PGSQL, error)
+//
+// 注入的 *T 与子配置是启动时的快照，需要热更新的值通过 ConfigService.Get 读取或 Subscribe 订阅
+
+// ConfigOptions 配置来源
+type ConfigOptions struct {
+	Dir        string // 配置目录，相对于工作目录，默认工作目录
+	Name       string // 基础配置文件名 (不含扩展名)，默认 "config"
+	Env        string // 叠加 <Name>.<Env>.yaml，默认取 APP_ENV，文件不存在时忽略
+	EnvPrefix  string // 环境变量前缀，如 "BLOG" 时 pgsql.host 对应 BLOG_PGSQL_HOST
+	SecretsDir string // 密钥文件目录，文件名为配置键 (pgsql.password) 或其环境变量名 (PGSQL_PASSWORD)，不存在时忽略
+	Watch      bool   // 监听配置文件变化并热更新
+}
+
+// ConfigService 类型化的配置，Get 总是返回最近一次校验通过的配置
+type ConfigService[T any] struct {
+	opts     ConfigOptions
+	current  atomic.Pointer[T]
+	keys     []string // T 中所有叶子字段的配置键，如 pgsql.host
+	validate *validator.Validate
+	mu       sync.Mutex
+	subs     map[uint64]func(old, new *T)
+	nextID   uint64
+	watcher  *fsnotify.Watcher // Watch 时监听配置目录，Close 时关闭
+	watching sync.WaitGroup
+}
+
+// ConfigModule 创建全局的配置模块
+func ConfigModule[T any](opts ConfigOptions) *Module {
+	svcType := reflect.TypeOf((*ConfigService[T])(nil))
+	m := &Module{
+		Name:   "ConfigModule",
+		Global: true,
+		Providers: []interface{}{
+			Factory(func() (*ConfigService[T], error) { return NewConfigService[T](opts) }),
+			Factory(func(s *ConfigService[T]) *T { return s.Get() }),
+		},
+		Exports: []interface{}{svcType, reflect.TypeOf((*T)(nil))},
+	}
+	// 子配置：T 中每个具名结构体字段各自作为 Provider
+	t := typeOf[T]()
+	seen := make(map[reflect.Type]bool)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() || f.Type.Kind() != reflect.Struct || f.Type.Name() == "" || f.Type == timeType || seen[f.Type] {
+			continue
+		}
+		seen[f.Type] = true
+		idx, out := i, reflect.PointerTo(f.Type)
+		fn := reflect.MakeFunc(reflect.FuncOf([]reflect.Type{svcType}, []reflect.Type{out}, false), func(args []reflect.Value) []reflect.Value {
+			sub := reflect.New(f.Type)
+			sub.Elem().Set(args[0].MethodByName("Get").Call(nil)[0].Elem().Field(idx))
+			return []reflect.Value{sub}
+		})
+		m.Providers = append(m.Providers, Factory(fn.Interface()))
+		m.Exports = append(m.Exports, out)
+	}
+	return m
+}
+
+// NewConfigService 加载并校验配置，Watch 为 true 时开始监听配置文件
+func NewConfigService[T any](opts ConfigOptions) (*ConfigService[T], error) {
+	if opts.Name == "" {
+		opts.Name = "config"
+	}
+	if opts.Env == "" {
+		opts.Env = os.Getenv("APP_ENV")
+	}
+	s := &ConfigService[T]{
+		opts:     opts,
+		keys:     configKeys(typeOf[T](), ""),
+		validate: validator.New(),
+		subs:     make(map[uint64]func(old, new *T)),
+	}
+	cfg, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	s.current.Store(cfg)
+	if opts.Watch {
+		if err := s.watch(); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// Close 停止监听配置文件，返回前进行中的 Reload 已结束
+func (s *ConfigService[T]) Close() error {
+	if s.watcher == nil {
+		return nil
+	}
+	err := s.watcher.Close()
+	s.watching.Wait()
+	return err
+}
+
+// OnModuleDestroy 应用关闭时停止监听配置文件
+func (s *ConfigService[T]) OnModuleDestroy(ctx context.Context) error {
+	return s.Close()
+}
+
+// Get 返回当前配置，调用方不应修改
+func (s *ConfigService[T]) Get() *T {
+	return s.current.Load()
+}
+
+// Subscribe 订阅热更新，回调在新配置生效后同步执行，返回取消订阅函数
+func (s *ConfigService[T]) Subscribe(fn func(old, new *T)) func() {
+	s.mu.Lock()
+	s.nextID++
+	id := s.nextID
+	s.subs[id] = fn
+	s.mu.Unlock()
+	return func() {
+		s.mu.Lock()
+		delete(s.subs, id)
+		s.mu.Unlock()
+	}
+}
+
+// Reload 重新加载配置，校验失败时保留当前配置并返回错误
+func (s *ConfigService[T]) Reload() error {
+	cfg, err := s.load()
+	if err != nil {
+		return err
+	}
+	old := s.current.Swap(cfg)
+	s.mu.Lock()
+	subs := make([]func(old, new *T), 0, len(s.subs))
+	for _, fn := range s.subs {
+		subs = append(subs, fn)
+	}
+	s.mu.Unlock()
+	for _, fn := range subs {
+		s.notify(fn, old, cfg)
+	}
+	return nil
+}
+
+func (s *ConfigService[T]) notify(fn func(old, new *T), old, cfg *T) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("[Gnest] config subscriber panicked: %v", r)
+		}
+	}()
+	fn(old, cfg)
+}
+
+func (s *ConfigService[T]) path(env string) string {
+	name := s.opts.Name
+	if env != "" {
+		name += "." + env
+	}
+	return filepath.Join(s.opts.Dir, name+".yaml")
+}
+
+// load 按层叠加配置来源并校验
+func (s *ConfigService[T]) load() (*T, error) {
+	v := viper.New()
+	v.SetConfigFile(s.path(""))
+	if err := v.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("config: %w", err)
+	}
+	if s.opts.Env != "" {
+		if _, err := os.Stat(s.path(s.opts.Env)); err == nil {
+			v.SetConfigFile(s.path(s.opts.Env))
+			if err := v.MergeInConfig(); err != nil {
+				return nil, fmt.Errorf("config: %w", err)
+			}
+		}
+	}
+
+	// 环境变量：viper 只在 Unmarshal 时识别已知的键，因此逐个绑定
+	v.SetEnvPrefix(s.opts.EnvPrefix)
+	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	for _, key := range s.keys {
+		_ = v.BindEnv(key)
+	}
+	if err := s.applySecrets(v); err != nil {
+		return nil, err
+	}
+
+	cfg := new(T)
+	if err := v.Unmarshal(cfg); err != nil {
+		return nil, fmt.Errorf("config: %w", err)
+	}
+	if err := s.validate.Struct(cfg); err != nil {
+		return nil, fmt.Errorf("config: invalid configuration: %w", err)
+	}
+	return cfg, nil
+}
+
+// applySecrets 读取密钥文件：<ENV>_FILE 环境变量指向的文件，以及 SecretsDir 中以配置键命名的文件
+func (s *ConfigService[T]) applySecrets(v *viper.Viper) error {
+	byName := make(map[string]string, len(s.keys)*2)
+	for _, key := range s.keys {
+		envName := strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+		byName[key] = key
+		byName[strings.ToLower(envName)] = key
+		if s.opts.EnvPrefix != "" {
+			envName = strings.ToUpper(s.opts.EnvPrefix) + "_" + envName
+		}
+		if file := os.Getenv(envName + "_FILE"); file != "" {
+			if err := setSecret(v, key, file); err != nil {
+				return err
+			}
+		}
+	}
+	if s.opts.SecretsDir == "" {
+		return nil
+	}
+	entries, err := os.ReadDir(s.opts.SecretsDir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("config: %w", err)
+	}
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		if key, ok := byName[strings.ToLower(e.Name())]; ok {
+			if err := setSecret(v, key, filepath.Join(s.opts.SecretsDir, e.Name())); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func setSecret(v *viper.Viper, key, file string) error {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return fmt.Errorf("config: secret for %s: %w", key, err)
+	}
+	v.Set(key, strings.TrimSpace(string(data)))
+	return nil
+}
+
+// watch 监听基础配置与环境配置文件所在的目录 (编辑器保存时常以新文件替换原文件)，
+// 相关文件写入或重建时 Reload，失败只记录日志
+func (s *ConfigService[T]) watch() error {
+	files := map[string]bool{filepath.Clean(s.path("")): true}
+	if s.opts.Env != "" {
+		if _, err := os.Stat(s.path(s.opts.Env)); err == nil {
+			files[filepath.Clean(s.path(s.opts.Env))] = true
+		}
+	}
+	w, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("config: watch: %w", err)
+	}
+	for file := range files {
+		if err := w.Add(filepath.Dir(file)); err != nil {
+			w.Close()
+			return fmt.Errorf("config: watch %s: %w", file, err)
+		}
+	}
+	s.watcher = w
+	s.watching.Add(1)
+	go func() {
+		defer s.watching.Done()
+		for {
+			select {
+			case e, ok := <-w.Events:
+				if !ok {
+					return
+				}
+				if !files[filepath.Clean(e.Name)] || !e.Has(fsnotify.Write|fsnotify.Create) {
+					continue
+				}
+				if err := s.Reload(); err != nil {
+					log.Printf("[Gnest] config reload (%s) failed, keeping current configuration: %v", e.Name, err)
+				}
+			case err, ok := <-w.Errors:
+				if !ok {
+					return
+				}
+				log.Printf("[Gnest] config watch error: %v", err)
+			}
+		}
+	}()
+	return nil
+}
+
+// configKeys 列出结构体所有叶子字段的配置键，map 与 time.Time 视为叶子
+func configKeys(t reflect.Type, prefix string) []string {
+	var keys []string
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name := strings.ToLower(f.Name)
+		if tag, ok := f.Tag.Lookup("mapstructure"); ok {
+			if tag, _, _ = strings.Cut(tag, ","); tag == "-" {
+				continue
+			} else if tag != "" {
+				name = strings.ToLower(tag)
+			}
+		}
+		key := prefix + name
+		if ft := derefType(f.Type); ft.Kind() == reflect.Struct && ft != timeType {
+			keys = append(keys, configKeys(ft, key+".")...)
+			continue
+		}
+		keys = append(keys, key)
+	}
+	return keys
+}
@@ -0,0 +1,136 @@
+package gnest_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"blog/internal/infra/gnest"
+)
+
+type dbSettings struct {
+	Host     string
+	User     string
+	Password string
+}
+
+type appSettings struct {
+	Server struct {
+		Port int `validate:"required"`
+	}
+	DB dbSettings
+}
+
+func writeConfig(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestConfigServicePrecedence(t *testing.T) {
+	dir, secrets := t.TempDir(), t.TempDir()
+	writeConfig(t, filepath.Join(dir, "config.yaml"), "server:\n  port: 8080\ndb:\n  host: base\n  user: base\n  password: base\n")
+	writeConfig(t, filepath.Join(dir, "config.test.yaml"), "db:\n  user: env-file\n  password: env-file\n")
+	writeConfig(t, filepath.Join(secrets, "db.password"), "secret\n")
+	t.Setenv("GNESTCFG_DB_PASSWORD", "env-var")
+
+	cases := []struct {
+		name string
+		opts gnest.ConfigOptions
+		want dbSettings
+	}{
+		{"base file", gnest.ConfigOptions{Dir: dir}, dbSettings{"base", "base", "base"}},
+		{"env file overrides base", gnest.ConfigOptions{Dir: dir, Env: "test"}, dbSettings{"base", "env-file", "env-file"}},
+		{"env var overrides files", gnest.ConfigOptions{Dir: dir, Env: "test", EnvPrefix: "GNESTCFG"}, dbSettings{"base", "env-file", "env-var"}},
+		{"secret overrides env var", gnest.ConfigOptions{Dir: dir, Env: "test", EnvPrefix: "GNESTCFG", SecretsDir: secrets}, dbSettings{"base", "env-file", "secret"}},
+		{"missing env file is ignored", gnest.ConfigOptions{Dir: dir, Env: "staging"}, dbSettings{"base", "base", "base"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			svc, err := gnest.NewConfigService[appSettings](tc.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := svc.Get(); got.DB != tc.want || got.Server.Port != 8080 {
+				t.Errorf("expected %+v on port 8080, got %+v", tc.want, *got)
+			}
+		})
+	}
+}
+
+func TestConfigModuleRejectsInvalidConfig(t *testing.T) {
+	dir := t.TempDir()
+	writeConfig(t, filepath.Join(dir, "config.yaml"), "db:\n  host: base\n")
+	app := gnest.NewTestingApp(gnest.ConfigModule[appSettings](gnest.ConfigOptions{Dir: dir}))
+	if err := app.Compile(); err == nil || !strings.Contains(err.Error(), "invalid configuration") {
+		t.Fatalf("expected startup to fail validation, got %v", err)
+	}
+}
+
+func TestConfigServiceReload(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "config.yaml")
+	writeConfig(t, file, "server:\n  port: 8080\n")
+	svc, err := gnest.NewConfigService[appSettings](gnest.ConfigOptions{Dir: dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var notified [][2]int
+	svc.Subscribe(func(old, new *appSettings) {
+		notified = append(notified, [2]int{old.Server.Port, new.Server.Port})
+	})
+
+	writeConfig(t, file, "server:\n  port: 0\n")
+	if err := svc.Reload(); err == nil {
+		t.Fatal("expected the invalid file to be rejected")
+	}
+	if port := svc.Get().Server.Port; port != 8080 || len(notified) != 0 {
+		t.Fatalf("expected the old config to stay without notifications, got port %d, %v", port, notified)
+	}
+
+	writeConfig(t, file, "server:\n  port: 9090\n")
+	if err := svc.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	if port := svc.Get().Server.Port; port != 9090 || len(notified) != 1 || notified[0] != [2]int{8080, 9090} {
+		t.Fatalf("expected subscribers to see 8080 -> 9090, got port %d, %v", port, notified)
+	}
+}
+
+func TestConfigServiceWatchStopsOnClose(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "config.yaml")
+	writeConfig(t, file, "server:\n  port: 8080\n")
+	svc, err := gnest.NewConfigService[appSettings](gnest.ConfigOptions{Dir: dir, Watch: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	changed := make(chan int, 16)
+	svc.Subscribe(func(_, new *appSettings) { changed <- new.Server.Port })
+
+	writeConfig(t, file, "server:\n  port: 9090\n")
+	select {
+	case port := <-changed:
+		if port != 9090 {
+			t.Fatalf("expected the watched change, got %d", port)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected a reload after the file changed")
+	}
+
+	if err := svc.OnModuleDestroy(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	for len(changed) > 0 {
+		<-changed
+	}
+	writeConfig(t, file, "server:\n  port: 7070\n")
+	time.Sleep(100 * time.Millisecond)
+	if len(changed) != 0 || svc.Get().Server.Port != 9090 {
+		t.Errorf("expected no reloads after Close, got port %d", svc.Get().Server.Port)
+	}
+}
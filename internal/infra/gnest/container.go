@@ -0,0 +1,521 @@
+package gnest
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ==========================================
+// 依赖注入容器 (Provider & Resolver)
+// ==========================================
+
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+// ErrProviderNotFound 依赖缺失时返回的错误，可用 errors.Is 判断
+var ErrProviderNotFound = errors.New("no provider registered")
+
+// Scope Provider 的生命周期
+type Scope int
+
+const (
+	Singleton Scope = iota // 默认：整个应用共享一个实例
+	Request                // 每个请求构建一次，请求结束时销毁
+	Transient              // 每次注入都构建新实例
+)
+
+func (s Scope) String() string {
+	switch s {
+	case Request:
+		return "request"
+	case Transient:
+		return "transient"
+	default:
+		return "singleton"
+	}
+}
+
+// requestBuiltins 请求作用域内可直接注入的内置对象
+var requestBuiltins = map[reflect.Type]func(c *gin.Context) reflect.Value{
+	reflect.TypeOf((*gin.Context)(nil)):  func(c *gin.Context) reflect.Value { return reflect.ValueOf(c) },
+	reflect.TypeOf((*http.Request)(nil)): func(c *gin.Context) reflect.Value { return reflect.ValueOf(c.Request) },
+}
+
+// token 唯一标识一个 Provider：类型 + 可选名称 (对应 inject:"name")
+type token struct {
+	typ  reflect.Type
+	name string
+}
+
+func (t token) String() string {
+	if t.name != "" {
+		return fmt.Sprintf("%v(%q)", t.typ, t.name)
+	}
+	return fmt.Sprint(t.typ)
+}
+
+// Provider 是 Factory / As / Named 构造出的提供者声明，
+// 可以放进 Module.Providers / Module.Exports，也可以直接交给 app.Provide
+type Provider struct {
+	tok     token
+	value   reflect.Value // 现成的实例
+	factory reflect.Value // 工厂函数：func(deps...) T 或 func(deps...) (T, error)
+	scope   Scope
+	err     error // 声明期发现的问题，启动时统一报告
+}
+
+// Factory 声明一个工厂 Provider，参数按类型从容器解析，返回值类型即令牌
+func Factory(fn interface{}) Provider {
+	v := reflect.ValueOf(fn)
+	if v.Kind() != reflect.Func {
+		return Provider{err: fmt.Errorf("factory must be a function, got %T", fn)}
+	}
+	t := v.Type()
+	if t.NumOut() == 0 || t.NumOut() > 2 || (t.NumOut() == 2 && t.Out(1) != errorType) {
+		return Provider{err: fmt.Errorf("factory %v must return T or (T, error)", t)}
+	}
+	return Provider{tok: token{typ: t.Out(0)}, factory: v}
+}
+
+// As 将实现绑定到接口令牌 I，impl 可以是实例或 Factory(...)
+func As[I any](impl interface{}) Provider {
+	iface := reflect.TypeOf((*I)(nil)).Elem()
+	p := toProvider(impl)
+	if p.err != nil {
+		return p
+	}
+	if iface.Kind() != reflect.Interface {
+		p.err = fmt.Errorf("As[%v]: type parameter must be an interface", iface)
+		return p
+	}
+	if !p.tok.typ.Implements(iface) {
+		p.err = fmt.Errorf("As[%v]: %v does not implement the interface", iface, p.tok.typ)
+		return p
+	}
+	p.tok.typ = iface
+	return p
+}
+
+// Named 为 Provider 加上名称，用于同一类型的多个实例 (如主库 / 从库)
+func Named(name string, impl interface{}) Provider {
+	p := toProvider(impl)
+	p.tok.name = name
+	return p
+}
+
+// Scoped 为 Provider 指定生命周期；Request / Transient 的实例 Provider 会以自身为原型复制后再注入
+func Scoped(scope Scope, impl interface{}) Provider {
+	p := toProvider(impl)
+	p.scope = scope
+	return p
+}
+
+func toProvider(x interface{}) Provider {
+	if p, ok := x.(Provider); ok {
+		return p
+	}
+	v := reflect.ValueOf(x)
+	if !v.IsValid() {
+		return Provider{err: errors.New("nil provider")}
+	}
+	return Provider{tok: token{typ: v.Type()}, value: v}
+}
+
+// providerDef 是登记到容器中的 Provider，实例在首次需要时才构建
+type providerDef struct {
+	tok      token
+	value    reflect.Value
+	factory  reflect.Value
+	module   *moduleRef // 所属模块，决定依赖从哪个作用域解析；nil 为根容器
+	scope    Scope
+	bound    int8 // 是否依赖请求上下文：0 未计算 / 1 否 / 2 是；resolveAll 中预先计算，运行期只读
+	resolved bool // resolved / instance / err 在运行期只在 app.mu 内读写
+	instance reflect.Value
+	err      error // 构建失败的原因，避免依赖它的 Provider 重复构建并重复报错
+}
+
+func newProviderDef(x interface{}, module *moduleRef) (*providerDef, error) {
+	p := toProvider(x)
+	if p.err != nil {
+		return nil, p.err
+	}
+	return &providerDef{tok: p.tok, value: p.value, factory: p.factory, scope: p.scope, module: module}, nil
+}
+
+// deps 静态列出 Provider 的依赖：工厂参数，或实例结构体上可注入的导出字段
+func (app *GnestApp) deps(def *providerDef) []token {
+	if def.factory.IsValid() {
+		ft := def.factory.Type()
+		out := make([]token, ft.NumIn())
+		for i := range out {
+			out[i] = token{typ: ft.In(i)}
+		}
+		return out
+	}
+	t := def.value.Type()
+	if t.Kind() != reflect.Ptr || t.Elem().Kind() != reflect.Struct {
+		return nil
+	}
+	var out []token
+	for i := 0; i < t.Elem().NumField(); i++ {
+		sf := t.Elem().Field(i)
+		name, tagged := sf.Tag.Lookup("inject")
+		if !sf.IsExported() || name == "-" {
+			continue
+		}
+		dep := token{typ: sf.Type, name: name}
+		if _, ok := app.lookup(def.module, dep); ok || tagged || isBuiltin(dep) {
+			out = append(out, dep)
+		}
+	}
+	return out
+}
+
+func isBuiltin(tok token) bool {
+	_, ok := requestBuiltins[tok.typ]
+	return ok && tok.name == ""
+}
+
+// requestBound 判断 Provider 是否只能在请求内构建：自身为 Request 作用域，
+// 或 (传递地) 依赖了请求作用域的 Provider / 请求内置对象。单例会因此被提升为请求作用域
+func (app *GnestApp) requestBound(def *providerDef, visiting map[*providerDef]bool) bool {
+	if def.bound != 0 {
+		return def.bound == 2
+	}
+	if visiting[def] {
+		return false // 循环依赖由 resolve 报告
+	}
+	visiting[def] = true
+	defer delete(visiting, def)
+
+	bound := def.scope == Request
+	for _, dep := range app.deps(def) {
+		if bound {
+			break
+		}
+		if d, ok := app.lookup(def.module, dep); ok {
+			bound = app.requestBound(d, visiting)
+		} else {
+			bound = isBuiltin(dep)
+		}
+	}
+	def.bound = 1
+	if bound {
+		def.bound = 2
+	}
+	return bound
+}
+
+// lifetime 返回 Provider 实际生效的生命周期
+func (app *GnestApp) lifetime(def *providerDef) Scope {
+	if def.scope == Transient {
+		return Transient
+	}
+	if app.requestBound(def, make(map[*providerDef]bool)) {
+		return Request
+	}
+	return Singleton
+}
+
+// checkDeps 启动时校验依赖是否都能找到，避免请求作用域的 Provider 到运行期才暴露问题
+func (app *GnestApp) checkDeps(def *providerDef) error {
+	for i, dep := range app.deps(def) {
+		if _, ok := app.lookup(def.module, dep); ok || isBuiltin(dep) {
+			continue
+		}
+		return app.missing(def, dep, i)
+	}
+	return nil
+}
+
+func (app *GnestApp) missing(def *providerDef, dep token, i int) error {
+	if def.factory.IsValid() {
+		return fmt.Errorf("cannot resolve %v (argument %d of the factory for %v)%s: %w",
+			dep, i, def.tok, def.module.where(), ErrProviderNotFound)
+	}
+	return fmt.Errorf("cannot resolve %v (field of %v)%s: %w", dep, def.tok, def.module.where(), ErrProviderNotFound)
+}
+
+// ProvideFactory 注册工厂 Provider
+func (app *GnestApp) ProvideFactory(fns ...interface{}) *GnestApp {
+	for _, fn := range fns {
+		app.Provide(Factory(fn))
+	}
+	return app
+}
+
+// ProvideScoped 以指定生命周期注册 Provider
+func (app *GnestApp) ProvideScoped(scope Scope, ps ...interface{}) *GnestApp {
+	for _, p := range ps {
+		app.Provide(Scoped(scope, p))
+	}
+	return app
+}
+
+// ProvideNamed 注册具名 Provider，字段通过 inject:"name" 取用
+func (app *GnestApp) ProvideNamed(name string, v interface{}) *GnestApp {
+	return app.Provide(Named(name, v))
+}
+
+// ProvideAs 以接口 I 为令牌注册实现 (泛型函数：Go 不支持泛型方法)
+func ProvideAs[I any](app *GnestApp, impl interface{}) *GnestApp {
+	return app.Provide(As[I](impl))
+}
+
+// lookup 在给定作用域查找 Provider，scope 为 nil 时使用根容器
+func (app *GnestApp) lookup(scope *moduleRef, tok token) (*providerDef, bool) {
+	if scope != nil {
+		return scope.lookup(app, tok)
+	}
+	def, ok := app.providers[tok]
+	return def, ok
+}
+
+// resolve 深度优先构建依赖：依赖总是先于使用者完成，因此 app.container 即为拓扑序。
+// rs 为当前请求作用域，启动阶段为 nil
+func (app *GnestApp) resolve(def *providerDef, rs *requestScope, stack []*providerDef) (reflect.Value, error) {
+	switch app.lifetime(def) {
+	case Request:
+		if rs == nil {
+			return reflect.Value{}, fmt.Errorf("%v is request-scoped and can only be resolved while handling a request", def.tok)
+		}
+		if v, ok := rs.instances[def]; ok {
+			return v, nil
+		}
+		inst, err := app.construct(def, rs, stack)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		rs.instances[def] = inst
+		rs.track(inst)
+		return inst, nil
+	case Transient:
+		inst, err := app.construct(def, rs, stack)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		if rs != nil {
+			rs.track(inst)
+		} else {
+			app.track(inst)
+		}
+		return inst, nil
+	}
+
+	if rs != nil {
+		return app.resolveShared(def, stack)
+	}
+	if def.resolved {
+		return def.instance, nil
+	}
+	if def.err != nil {
+		return reflect.Value{}, def.err
+	}
+	inst, err := app.construct(def, nil, stack)
+	if err != nil {
+		def.err = err
+		return reflect.Value{}, err
+	}
+	def.instance, def.resolved = inst, true
+	app.track(inst)
+	return inst, nil
+}
+
+// resolveShared 在运行期 (请求 / 网关消息) 解析单例：状态在锁内读写，
+// 首次用到时加锁构建，且不能捕获任何请求内的对象
+func (app *GnestApp) resolveShared(def *providerDef, stack []*providerDef) (reflect.Value, error) {
+	app.mu.Lock()
+	defer app.mu.Unlock()
+	return app.resolve(def, nil, stack)
+}
+
+// construct 检测循环依赖后构建一个新实例
+func (app *GnestApp) construct(def *providerDef, rs *requestScope, stack []*providerDef) (reflect.Value, error) {
+	for i, d := range stack {
+		if d == def {
+			path := make([]string, 0, len(stack)-i+1)
+			for _, s := range stack[i:] {
+				path = append(path, s.tok.String())
+			}
+			path = append(path, def.tok.String())
+			return reflect.Value{}, fmt.Errorf("circular dependency: %s", strings.Join(path, " -> "))
+		}
+	}
+	return app.build(def, rs, append(stack, def))
+}
+
+// dep 解析单个依赖：先查 Provider，再查请求内置对象
+func (app *GnestApp) dep(def *providerDef, tok token, rs *requestScope, stack []*providerDef) (reflect.Value, bool, error) {
+	if d, ok := app.lookup(def.module, tok); ok {
+		v, err := app.resolve(d, rs, stack)
+		return v, true, err
+	}
+	if rs != nil && isBuiltin(tok) {
+		return requestBuiltins[tok.typ](rs.ctx), true, nil
+	}
+	return reflect.Value{}, false, nil
+}
+
+func (app *GnestApp) build(def *providerDef, rs *requestScope, stack []*providerDef) (reflect.Value, error) {
+	if def.factory.IsValid() {
+		ft := def.factory.Type()
+		args := make([]reflect.Value, ft.NumIn())
+		for i := range args {
+			v, ok, err := app.dep(def, token{typ: ft.In(i)}, rs, stack)
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			if !ok {
+				return reflect.Value{}, app.missing(def, token{typ: ft.In(i)}, i)
+			}
+			args[i] = v
+		}
+		out := def.factory.Call(args)
+		if len(out) == 2 && !out[1].IsNil() {
+			return reflect.Value{}, fmt.Errorf("factory for %v failed: %w", def.tok, out[1].Interface().(error))
+		}
+		return out[0], nil
+	}
+
+	inst := def.value
+	if app.lifetime(def) != Singleton {
+		// 非单例：以注册的实例为原型复制出新对象
+		inst = clonePrototype(def.value)
+	}
+	if err := app.injectFields(def, inst, rs, stack); err != nil {
+		return reflect.Value{}, err
+	}
+	return inst, nil
+}
+
+// clonePrototype 浅拷贝结构体指针，其余类型无法复制，直接复用
+func clonePrototype(v reflect.Value) reflect.Value {
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return v
+	}
+	nv := reflect.New(v.Elem().Type())
+	nv.Elem().Set(v.Elem())
+	return nv
+}
+
+// injectFields 填充结构体的导出字段：
+// 无 tag 的字段类型匹配则注入；inject:"name" 取具名 Provider 且必须存在；inject:"-" 跳过
+func (app *GnestApp) injectFields(def *providerDef, v reflect.Value, rs *requestScope, stack []*providerDef) error {
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return nil
+	}
+	el := v.Elem()
+	st := el.Type()
+	for i := 0; i < el.NumField(); i++ {
+		f := el.Field(i)
+		if !f.CanSet() {
+			continue
+		}
+		sf := st.Field(i)
+		name, tagged := sf.Tag.Lookup("inject")
+		if name == "-" {
+			continue
+		}
+		dep := token{typ: sf.Type, name: name}
+		dv, ok, err := app.dep(def, dep, rs, stack)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			if tagged {
+				return fmt.Errorf("cannot resolve %v (field %v.%s)%s: %w", dep, st, sf.Name, def.module.where(), ErrProviderNotFound)
+			}
+			continue
+		}
+		f.Set(dv)
+	}
+	return nil
+}
+
+// track 按构建顺序记录实例，供生命周期钩子扫描；同一实例以多个令牌注册时只记录一次
+func (app *GnestApp) track(inst reflect.Value) {
+	if !inst.IsValid() {
+		return
+	}
+	if inst.Comparable() {
+		key := inst.Interface()
+		if app.tracked[key] {
+			return
+		}
+		app.tracked[key] = true
+	}
+	if a, ok := inst.Interface().(appAware); ok {
+		a.setApp(app)
+	}
+	app.container = append(app.container, inst.Interface())
+}
+
+// appAware 由需要扫描应用容器的内置 Provider 实现，如 SchedulerRegistry
+type appAware interface{ setApp(app *GnestApp) }
+
+// resolveAll 校验所有 Provider 的依赖并按登记顺序构建单例，返回遇到的全部错误；
+// 生命周期在此一次性算出，运行期并发的请求只读取
+func (app *GnestApp) resolveAll(defs []*providerDef) error {
+	app.mu.Lock()
+	defer app.mu.Unlock()
+	for _, def := range defs {
+		app.lifetime(def)
+	}
+	var errs []error
+	seen := make(map[error]bool)
+	for _, def := range defs {
+		err := app.checkDeps(def)
+		if err == nil && app.lifetime(def) == Singleton {
+			_, err = app.resolve(def, nil, nil)
+		}
+		if err != nil && !seen[err] {
+			seen[err] = true
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ==========================================
+// 请求作用域 (Request Scope)
+// ==========================================
+
+const requestScopeKey = "gnest.requestScope"
+
+// requestScope 保存一次请求内构建的实例，请求结束时按创建逆序触发 OnRequestDestroy
+type requestScope struct {
+	ctx       *gin.Context
+	instances map[*providerDef]reflect.Value
+	created   []interface{}
+}
+
+func (app *GnestApp) beginRequest(c *gin.Context) *requestScope {
+	rs := &requestScope{ctx: c, instances: make(map[*providerDef]reflect.Value)}
+	c.Set(requestScopeKey, rs)
+	return rs
+}
+
+func requestScopeOf(c *gin.Context) *requestScope {
+	if v, ok := c.Get(requestScopeKey); ok {
+		return v.(*requestScope)
+	}
+	return nil
+}
+
+func (rs *requestScope) track(inst reflect.Value) {
+	if inst.IsValid() && inst.CanInterface() {
+		rs.created = append(rs.created, inst.Interface())
+	}
+}
+
+func (rs *requestScope) destroy() {
+	for i := len(rs.created) - 1; i >= 0; i-- {
+		if h, ok := rs.created[i].(OnRequestDestroy); ok {
+			h.OnRequestDestroy()
+		}
+	}
+}
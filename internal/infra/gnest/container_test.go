@@ -0,0 +1,72 @@
+package gnest_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"blog/internal/infra/gnest"
+)
+
+// requestInfo 请求作用域的 Provider，依赖它的控制器随之提升为请求作用域
+type requestInfo struct{}
+
+type scopedController struct {
+	Info *requestInfo
+	hits int
+}
+
+func (c *scopedController) Prefix() string { return "/scoped" }
+func (c *scopedController) Routes(rg *gnest.RouterGroup) {
+	rg.GET("/hit", (*scopedController).Hit)
+}
+
+// Hit 每个请求都是新实例，hits 不会在请求间累加
+func (c *scopedController) Hit() string {
+	c.hits++
+	return fmt.Sprint(c.hits)
+}
+
+type valueController struct {
+	Info *requestInfo
+}
+
+func (c *valueController) Prefix() string { return "/value" }
+func (c *valueController) Routes(rg *gnest.RouterGroup) {
+	rg.GET("/", c.Get)
+}
+func (c *valueController) Get() string { return "ok" }
+
+func TestRequestScopedControllerConcurrent(t *testing.T) {
+	app := gnest.NewTestingApp(&gnest.Module{
+		Name:        "Scoped",
+		Providers:   []interface{}{gnest.Scoped(gnest.Request, &requestInfo{})},
+		Controllers: []gnest.Controller{&scopedController{}},
+	})
+	if err := app.Compile(); err != nil {
+		t.Fatal(err)
+	}
+	client := app.Client(t)
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if body := client.GET("/scoped/hit").Response().Body.String(); body != "1" {
+				t.Errorf("expected a fresh controller per request, got %q", body)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestRequestScopedControllerRejectsMethodValue(t *testing.T) {
+	app := gnest.NewTestingApp(&gnest.Module{
+		Name:        "Value",
+		Providers:   []interface{}{gnest.Scoped(gnest.Request, &requestInfo{})},
+		Controllers: []gnest.Controller{&valueController{}},
+	})
+	if err := app.Compile(); err == nil {
+		t.Fatal("expected a registration error for a method value on a request-scoped controller")
+	}
+}
@@ -0,0 +1,98 @@
+package gnest
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ==========================================
+// 请求上下文 (Request Context)
+// ==========================================
+
+// 每个请求的 context.Context 携带请求 ID 与守卫 / 中间件写入的当前用户，客户端断开或超时时被取消。
+// 处理函数声明 context.Context 参数即可获得，向下传给 Service / Repository / Redis / MinIO：
+//
+//	func (ctrl *PostController) Get(ctx context.Context, p *GetPostParams) (*post.Post, error) {
+//		return ctrl.Svc.Get(ctx, p.ID)
+//	}
+//
+//	rg.GET("/posts/:id", ctrl.Get, gnest.Timeout(3*time.Second)) // 超时取消 ctx 并返回 504
+
+// RequestIDHeader 请求 ID 的请求 / 响应头，请求未携带时生成
+const RequestIDHeader = "X-Request-Id"
+
+type requestIDKey struct{}
+type userKey struct{}
+
+// RequestID 读取 ctx 中的请求 ID
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// WithUser 返回携带当前用户的 ctx
+func WithUser(ctx context.Context, user interface{}) context.Context {
+	return context.WithValue(ctx, userKey{}, user)
+}
+
+// UserFrom 读取 ctx 中指定类型的当前用户
+//
+//	claims, ok := gnest.UserFrom[*jwt.StandardClaims](ctx)
+func UserFrom[T any](ctx context.Context) (T, bool) {
+	u, ok := ctx.Value(userKey{}).(T)
+	return u, ok
+}
+
+// SetUser 供鉴权守卫 / 中间件在验证通过后调用，之后的处理函数与下游调用可从 ctx 中读取
+func SetUser(c *gin.Context, user interface{}) {
+	c.Request = c.Request.WithContext(WithUser(c.Request.Context(), user))
+}
+
+// prepareContext 在守卫之前写入请求 ID，并回写到响应头
+func prepareContext(c *gin.Context) {
+	ctx := c.Request.Context()
+	if RequestID(ctx) != "" {
+		return
+	}
+	id := c.GetHeader(RequestIDHeader)
+	if id == "" {
+		id = uuid.NewString()
+	}
+	c.Header(RequestIDHeader, id)
+	c.Request = c.Request.WithContext(context.WithValue(ctx, requestIDKey{}, id))
+}
+
+// TimeoutInterceptor 为处理函数的 ctx 设置超时，超时后返回 504；可注册为全局拦截器作为默认超时，
+// 路由上的 Timeout(d) 嵌套在内层，取两者中较短的时间。
+// 拦截器不会抢占处理函数：504 在处理函数返回后才写出，忽略 ctx 的处理函数会一直执行到结束，
+// 因此处理函数需要将 ctx 传给下游调用才能在超时时提前结束。
+// 超时只覆盖处理函数本身，返回后恢复原来的 ctx，SSE / 流 / 分块等结果的写出不受其限制
+type TimeoutInterceptor struct {
+	Timeout time.Duration
+}
+
+// Timeout 创建路由 (组) 级的超时拦截器
+func Timeout(d time.Duration) *TimeoutInterceptor {
+	return &TimeoutInterceptor{Timeout: d}
+}
+
+func (t *TimeoutInterceptor) Intercept(c *gin.Context, next func() interface{}) interface{} {
+	if t.Timeout <= 0 {
+		return next()
+	}
+	parent := c.Request.Context()
+	ctx, cancel := context.WithTimeout(parent, t.Timeout)
+	defer cancel()
+	c.Request = c.Request.WithContext(ctx)
+
+	res := next()
+	c.Request = c.Request.WithContext(parent)
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) && !c.Writer.Written() {
+		return GatewayTimeout("request timed out").WithCause(ctx.Err())
+	}
+	return res
+}
@@ -0,0 +1,46 @@
+package gnest_test
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"blog/internal/infra/gnest"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestTimeoutInterceptorLeavesStreamsAlone(t *testing.T) {
+	app := gnest.NewTestingApp()
+	app.UseGlobalInterceptors(&gnest.TimeoutInterceptor{Timeout: time.Second})
+	app.GET("/stream", func() gnest.StreamResult {
+		return gnest.StreamResult{Reader: strings.NewReader("streamed"), ContentType: "text/plain"}
+	})
+	app.GET("/chunks", func() gnest.ChunkedResult {
+		return gnest.ChunkedResult{Generator: func(ctx context.Context, write func([]byte) error) error {
+			for _, chunk := range []string{"a", "b", "c"} {
+				if err := write([]byte(chunk)); err != nil {
+					return err
+				}
+			}
+			return nil
+		}}
+	})
+	app.GET("/slow", func(c *gin.Context) string {
+		<-c.Request.Context().Done()
+		return "late"
+	}, gnest.Timeout(10*time.Millisecond))
+	if err := app.Compile(); err != nil {
+		t.Fatal(err)
+	}
+
+	if body := app.Client(t).GET("/stream").ExpectStatus(http.StatusOK).Response().Body.String(); body != "streamed" {
+		t.Errorf("expected the stream body, got %q", body)
+	}
+	if body := app.Client(t).GET("/chunks").ExpectStatus(http.StatusOK).Response().Body.String(); body != "abc" {
+		t.Errorf("expected the chunked body, got %q", body)
+	}
+	app.Client(t).GET("/slow").ExpectStatus(http.StatusGatewayTimeout)
+}
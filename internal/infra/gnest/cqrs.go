@@ -0,0 +1,224 @@
+package gnest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ==========================================
+// 命令与查询总线 (CQRS)
+// ==========================================
+
+// 处理器是嵌入 CommandHandler[C] / QueryHandler[Q] 标记并实现 Execute 的 Provider，
+// 总线从容器中自动发现，每个命令 / 查询类型有且只有一个处理器：
+//
+//	type CreatePostHandler struct {
+//		gnest.CommandHandler[CreatePostCommand]
+//		Repo *PostRepository
+//	}
+//
+//	func (h *CreatePostHandler) Execute(ctx context.Context, cmd CreatePostCommand) (*Post, error)
+//
+// 控制器注入总线后直接调用：
+//
+//	func (ctrl *PostController) Create(c *gin.Context, cmd *CreatePostCommand) (interface{}, error) {
+//		return ctrl.Commands.Execute(c, *cmd)
+//	}
+
+// ErrNoHandler 命令 / 查询没有对应处理器时返回，可用 errors.Is 判断
+var ErrNoHandler = errors.New("no handler registered")
+
+// CommandHandler 嵌入到处理器结构体中，声明其处理命令 C
+type CommandHandler[C any] struct{}
+
+func (CommandHandler[C]) commandType() reflect.Type { return typeOf[C]() }
+
+// QueryHandler 嵌入到处理器结构体中，声明其处理查询 Q
+type QueryHandler[Q any] struct{}
+
+func (QueryHandler[Q]) queryType() reflect.Type { return typeOf[Q]() }
+
+type commandMarker interface{ commandType() reflect.Type }
+type queryMarker interface{ queryType() reflect.Type }
+
+// BusInterceptor 总线的管道行为 (校验、日志、事务等)，与 NestInterceptor 相同的洋葱模型：
+// 调用 next 进入内层，可在前后追加逻辑或直接返回以短路
+type BusInterceptor interface {
+	Intercept(ctx context.Context, msg interface{}, next func(ctx context.Context) (interface{}, error)) (interface{}, error)
+}
+
+// BusInterceptorFunc 将函数适配为 BusInterceptor
+type BusInterceptorFunc func(ctx context.Context, msg interface{}, next func(ctx context.Context) (interface{}, error)) (interface{}, error)
+
+func (f BusInterceptorFunc) Intercept(ctx context.Context, msg interface{}, next func(ctx context.Context) (interface{}, error)) (interface{}, error) {
+	return f(ctx, msg, next)
+}
+
+// messageBus 是 CommandBus 与 QueryBus 的公共实现
+type messageBus struct {
+	kind         string // command / query
+	handlerOf    func(inst interface{}) (reflect.Type, bool)
+	mu           sync.RWMutex
+	handlers     map[reflect.Type]reflect.Value // 消息类型 -> 处理器的 Execute 方法
+	interceptors []BusInterceptor
+	once         sync.Once
+	err          error
+	app          *GnestApp
+}
+
+// CommandBus 命令总线，作为内置 Provider 注册在根容器中
+type CommandBus struct{ messageBus }
+
+// QueryBus 查询总线，作为内置 Provider 注册在根容器中
+type QueryBus struct{ messageBus }
+
+// NewCommandBus 创建命令总线
+func NewCommandBus() *CommandBus {
+	return &CommandBus{messageBus{kind: "command", handlerOf: func(inst interface{}) (reflect.Type, bool) {
+		m, ok := inst.(commandMarker)
+		if !ok {
+			return nil, false
+		}
+		return m.commandType(), true
+	}}}
+}
+
+// NewQueryBus 创建查询总线
+func NewQueryBus() *QueryBus {
+	return &QueryBus{messageBus{kind: "query", handlerOf: func(inst interface{}) (reflect.Type, bool) {
+		m, ok := inst.(queryMarker)
+		if !ok {
+			return nil, false
+		}
+		return m.queryType(), true
+	}}}
+}
+
+func (b *messageBus) setApp(app *GnestApp) { b.app = app }
+
+// Use 追加管道行为，先注册的在外层
+func (b *messageBus) Use(is ...BusInterceptor) {
+	b.mu.Lock()
+	b.interceptors = append(b.interceptors, is...)
+	b.mu.Unlock()
+}
+
+// OnModuleInit 发现处理器，重复或签名不符时中止启动
+func (b *messageBus) OnModuleInit(ctx context.Context) error {
+	b.once.Do(b.discover)
+	return b.err
+}
+
+// discover 扫描容器中的处理器；未经 Bootstrap (如 TestingApp) 时在首次 Execute 前执行
+func (b *messageBus) discover() {
+	b.handlers = make(map[reflect.Type]reflect.Value)
+	if b.app == nil {
+		return
+	}
+	var errs []error
+	owners := make(map[reflect.Type]interface{})
+	for _, inst := range b.app.container {
+		msgType, ok := b.handlerOf(inst)
+		if !ok {
+			continue
+		}
+		if prev, dup := owners[msgType]; dup {
+			errs = append(errs, fmt.Errorf("%s %v is handled by both %T and %T", b.kind, msgType, prev, inst))
+			continue
+		}
+		m := reflect.ValueOf(inst).MethodByName("Execute")
+		if !m.IsValid() {
+			errs = append(errs, fmt.Errorf("%T: missing Execute(ctx context.Context, %v) (R, error)", inst, msgType))
+			continue
+		}
+		mt := m.Type()
+		if mt.NumIn() != 2 || mt.In(0) != contextType || mt.In(1) != msgType || mt.NumOut() != 2 || mt.Out(1) != errorType {
+			errs = append(errs, fmt.Errorf("%T.Execute must be func(context.Context, %v) (R, error), got %v", inst, msgType, mt))
+			continue
+		}
+		owners[msgType] = inst
+		b.handlers[msgType] = m
+	}
+	b.err = errors.Join(errs...)
+}
+
+// Execute 经过管道行为后交给唯一的处理器执行；ctx 可直接传 *gin.Context
+func (b *messageBus) Execute(ctx context.Context, msg interface{}) (interface{}, error) {
+	b.once.Do(b.discover)
+	if b.err != nil {
+		return nil, b.err
+	}
+	typ := reflect.TypeOf(msg)
+	b.mu.RLock()
+	h, ok := b.handlers[typ]
+	is := b.interceptors
+	b.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%s %v: %w", b.kind, typ, ErrNoHandler)
+	}
+
+	handle := func(ctx context.Context) (interface{}, error) {
+		if err := b.validate(ctx, msg); err != nil {
+			return nil, err
+		}
+		out := h.Call([]reflect.Value{reflect.ValueOf(ctx), reflect.ValueOf(msg)})
+		if err, _ := out[1].Interface().(error); err != nil {
+			return nil, err
+		}
+		return out[0].Interface(), nil
+	}
+	return execBusInterceptors(ctx, msg, is, 0, handle)
+}
+
+func execBusInterceptors(ctx context.Context, msg interface{}, is []BusInterceptor, i int, next func(ctx context.Context) (interface{}, error)) (interface{}, error) {
+	if i >= len(is) {
+		return next(ctx)
+	}
+	return is[i].Intercept(ctx, msg, func(ctx context.Context) (interface{}, error) {
+		return execBusInterceptors(ctx, msg, is, i+1, next)
+	})
+}
+
+// validate 按 validate 标签校验结构体消息，失败时返回与请求校验一致的 400 异常
+func (b *messageBus) validate(ctx context.Context, msg interface{}) error {
+	typ := derefType(reflect.TypeOf(msg))
+	if b.app == nil || typ.Kind() != reflect.Struct {
+		return nil
+	}
+	if err := b.app.validate.Struct(msg); err != nil {
+		c, _ := ctx.(*gin.Context)
+		return b.app.validationException(c, b.app.validate, typ, err)
+	}
+	return nil
+}
+
+// ExecuteCommand 执行命令并将结果断言为 R
+func ExecuteCommand[R any](ctx context.Context, bus *CommandBus, cmd interface{}) (R, error) {
+	return executeAs[R](ctx, &bus.messageBus, cmd)
+}
+
+// ExecuteQuery 执行查询并将结果断言为 R
+func ExecuteQuery[R any](ctx context.Context, bus *QueryBus, query interface{}) (R, error) {
+	return executeAs[R](ctx, &bus.messageBus, query)
+}
+
+func executeAs[R any](ctx context.Context, b *messageBus, msg interface{}) (R, error) {
+	var zero R
+	res, err := b.Execute(ctx, msg)
+	if err != nil {
+		return zero, err
+	}
+	if res == nil {
+		return zero, nil
+	}
+	r, ok := res.(R)
+	if !ok {
+		return zero, fmt.Errorf("%s %T returned %T, not %v", b.kind, msg, res, typeOf[R]())
+	}
+	return r, nil
+}
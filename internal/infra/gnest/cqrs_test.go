@@ -0,0 +1,195 @@
+package gnest_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"blog/internal/infra/gnest"
+)
+
+type createNote struct {
+	Title string `validate:"required"`
+}
+
+type noteCount struct{}
+
+type createNoteHandler struct {
+	gnest.CommandHandler[createNote]
+	calls int
+}
+
+func (h *createNoteHandler) Execute(ctx context.Context, cmd createNote) (string, error) {
+	h.calls++
+	return "created " + cmd.Title, nil
+}
+
+type otherCreateNoteHandler struct {
+	gnest.CommandHandler[createNote]
+}
+
+func (h *otherCreateNoteHandler) Execute(ctx context.Context, cmd createNote) (string, error) {
+	return "", nil
+}
+
+type noteCountHandler struct {
+	gnest.QueryHandler[noteCount]
+}
+
+func (h *noteCountHandler) Execute(ctx context.Context, q noteCount) (int, error) {
+	return 3, nil
+}
+
+type busConsumer struct {
+	Commands *gnest.CommandBus
+	Queries  *gnest.QueryBus
+}
+
+func compileBuses(t *testing.T, hooks bool, providers ...interface{}) (*busConsumer, error) {
+	t.Helper()
+	consumer := &busConsumer{}
+	app := gnest.NewTestingApp(&gnest.Module{Name: "Notes", Providers: append(providers, consumer)})
+	if hooks {
+		app.WithLifecycleHooks()
+		t.Cleanup(func() { app.Shutdown(context.Background(), "test") })
+	}
+	return consumer, app.Compile()
+}
+
+func TestBusDiscoversHandlers(t *testing.T) {
+	cases := []struct {
+		name    string
+		hooks   bool
+		dup     bool
+		wantErr string
+	}{
+		{name: "on module init", hooks: true},
+		{name: "lazily on first execute"},
+		{name: "duplicate fails startup", hooks: true, dup: true, wantErr: "handled by both"},
+		{name: "duplicate fails first execute", dup: true, wantErr: "handled by both"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			handler := &createNoteHandler{}
+			providers := []interface{}{handler, &noteCountHandler{}}
+			if tc.dup {
+				providers = append(providers, &otherCreateNoteHandler{})
+			}
+			buses, err := compileBuses(t, tc.hooks, providers...)
+			if tc.hooks && tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("expected startup error %q, got %v", tc.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			res, err := gnest.ExecuteCommand[string](context.Background(), buses.Commands, createNote{Title: "hello"})
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("expected execute error %q, got %v", tc.wantErr, err)
+				}
+				return
+			}
+			if err != nil || res != "created hello" || handler.calls != 1 {
+				t.Fatalf("expected the command handler to run once, got %q, %v (calls %d)", res, err, handler.calls)
+			}
+			n, err := gnest.ExecuteQuery[int](context.Background(), buses.Queries, noteCount{})
+			if err != nil || n != 3 {
+				t.Fatalf("expected the query handler result, got %d, %v", n, err)
+			}
+		})
+	}
+}
+
+func TestBusExecuteErrors(t *testing.T) {
+	buses, err := compileBuses(t, false, &createNoteHandler{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := []struct {
+		name  string
+		exec  func() error
+		check func(error) bool
+	}{
+		{
+			name:  "no command handler",
+			exec:  func() error { _, err := buses.Commands.Execute(context.Background(), noteCount{}); return err },
+			check: func(err error) bool { return errors.Is(err, gnest.ErrNoHandler) },
+		},
+		{
+			name:  "no query handler",
+			exec:  func() error { _, err := buses.Queries.Execute(context.Background(), createNote{}); return err },
+			check: func(err error) bool { return errors.Is(err, gnest.ErrNoHandler) },
+		},
+		{
+			name: "validation failure",
+			exec: func() error { _, err := buses.Commands.Execute(context.Background(), createNote{}); return err },
+			check: func(err error) bool {
+				var he *gnest.HttpException
+				return errors.As(err, &he) && he.Status == 400 && he.Code == gnest.CodeValidationFailed
+			},
+		},
+		{
+			name: "result type mismatch",
+			exec: func() error {
+				_, err := gnest.ExecuteCommand[int](context.Background(), buses.Commands, createNote{Title: "x"})
+				return err
+			},
+			check: func(err error) bool { return err != nil && strings.Contains(err.Error(), "returned string") },
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := tc.exec(); !tc.check(err) {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+type recordingBusInterceptor struct {
+	name string
+	log  *[]string
+}
+
+func (r recordingBusInterceptor) Intercept(ctx context.Context, msg interface{}, next func(ctx context.Context) (interface{}, error)) (interface{}, error) {
+	*r.log = append(*r.log, r.name+":before")
+	res, err := next(ctx)
+	*r.log = append(*r.log, r.name+":after")
+	return res, err
+}
+
+func TestBusInterceptorOrder(t *testing.T) {
+	handler := &createNoteHandler{}
+	buses, err := compileBuses(t, false, handler)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var log []string
+	buses.Commands.Use(recordingBusInterceptor{"outer", &log}, recordingBusInterceptor{"inner", &log})
+	buses.Commands.Use(gnest.BusInterceptorFunc(func(ctx context.Context, msg interface{}, next func(ctx context.Context) (interface{}, error)) (interface{}, error) {
+		log = append(log, "short-circuit")
+		if msg.(createNote).Title == "cached" {
+			return "from cache", nil
+		}
+		return next(ctx)
+	}))
+
+	if _, err := buses.Commands.Execute(context.Background(), createNote{Title: "hello"}); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"outer:before", "inner:before", "short-circuit", "inner:after", "outer:after"}
+	if !reflect.DeepEqual(log, want) {
+		t.Errorf("expected %v, got %v", want, log)
+	}
+
+	res, err := buses.Commands.Execute(context.Background(), createNote{Title: "cached"})
+	if err != nil || res != "from cache" || handler.calls != 1 {
+		t.Errorf("expected the innermost interceptor to short-circuit, got %v, %v (calls %d)", res, err, handler.calls)
+	}
+}
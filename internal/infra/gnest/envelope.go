@@ -0,0 +1,203 @@
+package gnest
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"net/http"
+	"reflect"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// ==========================================
+// 统一响应信封 (Response Envelope)
+// ==========================================
+
+// 注册为全局拦截器后，成功的返回值与过滤器输出的错误统一包装为 {code, message, data}：
+//
+//	envelope := &gnest.EnvelopeInterceptor{}
+//	app.Provide(envelope)
+//	app.UseGlobalInterceptors(envelope)
+//
+// 文件、重定向、流等返回值原样输出；其他需要原始响应的路由 (或路由组) 使用 RawResponse() 关闭包装
+
+// RawResponseKey 关闭响应信封的元数据键
+const RawResponseKey = "rawResponse"
+
+const envelopeCtxKey = "gnest.envelope"
+
+// RawResponse 标记路由不使用响应信封
+func RawResponse() Metadata {
+	return SetMetadata(RawResponseKey, true)
+}
+
+// EnvelopeOptions 信封的字段名与成功提示
+type EnvelopeOptions struct {
+	CodeKey        string // 默认 "code"
+	MessageKey     string // 默认 "message"
+	DataKey        string // 默认 "data"
+	SuccessMessage string // 默认 "success"
+}
+
+// EnvelopeInterceptor 响应信封拦截器，Options 未注入时使用默认字段名
+type EnvelopeInterceptor struct {
+	Options *EnvelopeOptions
+}
+
+// Envelope 包装后的响应，按 Options 中的字段名序列化
+type Envelope struct {
+	Code    int
+	Message string
+	Data    interface{}
+	opts    EnvelopeOptions
+}
+
+func (e *EnvelopeInterceptor) options() EnvelopeOptions {
+	var o EnvelopeOptions
+	if e.Options != nil {
+		o = *e.Options
+	}
+	if o.CodeKey == "" {
+		o.CodeKey = "code"
+	}
+	if o.MessageKey == "" {
+		o.MessageKey = "message"
+	}
+	if o.DataKey == "" {
+		o.DataKey = "data"
+	}
+	if o.SuccessMessage == "" {
+		o.SuccessMessage = "success"
+	}
+	return o
+}
+
+// prepareRequest 在守卫之前执行，使守卫拒绝等早于拦截器的错误也能被包装
+func (e *EnvelopeInterceptor) prepareRequest(c *gin.Context) {
+	if raw, _ := GetMetadata[bool](c, RawResponseKey); !raw {
+		c.Set(envelopeCtxKey, e)
+	}
+}
+
+func (e *EnvelopeInterceptor) Intercept(c *gin.Context, next func() interface{}) interface{} {
+	res := next()
+	if _, ok := c.Get(envelopeCtxKey); !ok || c.Writer.Written() || !isPlainResult(res) {
+		return res
+	}
+	o := e.options()
+	return Envelope{Code: http.StatusOK, Message: o.SuccessMessage, Data: res, opts: o}
+}
+
+// isPlainResult 返回值是否为普通数据 (由内容协商序列化)，错误、文件、重定向、流等返回 false
+func isPlainResult(res interface{}) bool {
+	switch res.(type) {
+	case error, Envelope, Render, RedirectResult, DataResult, FileResult, SSEResult, StreamResult, ChunkedResult, []byte:
+		return false
+	}
+	return true
+}
+
+// envelopeOf 返回当前路由生效的信封拦截器，未启用或 RawResponse 时为 nil
+func envelopeOf(c *gin.Context) *EnvelopeInterceptor {
+	v, _ := c.Get(envelopeCtxKey)
+	e, _ := v.(*EnvelopeInterceptor)
+	return e
+}
+
+// wrapError 将异常包装为信封，data 为完整的 ErrorBody
+func (e *EnvelopeInterceptor) wrapError(he *HttpException) Envelope {
+	return Envelope{Code: he.Status, Message: he.Message, Data: he.Body(), opts: e.options()}
+}
+
+func (e Envelope) MarshalJSON() ([]byte, error) {
+	return marshalFieldsJSON(e.fields())
+}
+
+// MarshalXML 以 <response> 为根元素，切片类型的 data 逐项写入
+func (e Envelope) MarshalXML(enc *xml.Encoder, start xml.StartElement) error {
+	return encodeFieldsXML(enc, xml.StartElement{Name: xml.Name{Local: "response"}}, e.fields())
+}
+
+func (e Envelope) EncodeMsgpack(enc *msgpack.Encoder) error {
+	return encodeFieldsMsgpack(enc, e.fields())
+}
+
+func (e Envelope) fields() []orderedField {
+	return []orderedField{
+		{e.opts.CodeKey, e.Code},
+		{e.opts.MessageKey, e.Message},
+		{e.opts.DataKey, e.Data},
+	}
+}
+
+// orderedField 有序对象的一个字段，信封与序列化结果按声明顺序输出
+type orderedField struct {
+	key   string
+	value interface{}
+}
+
+func marshalFieldsJSON(fields []orderedField) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, kv := range fields {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		k, _ := json.Marshal(kv.key)
+		v, err := json.Marshal(kv.value)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(k)
+		buf.WriteByte(':')
+		buf.Write(v)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// encodeFieldsXML 每个字段写为一个子元素，切片类型的值逐项写入
+func encodeFieldsXML(enc *xml.Encoder, start xml.StartElement, fields []orderedField) error {
+	if err := enc.EncodeToken(start); err != nil {
+		return err
+	}
+	for _, kv := range fields {
+		el := xml.StartElement{Name: xml.Name{Local: kv.key}}
+		rv := reflect.Indirect(reflect.ValueOf(kv.value))
+		if !isListKind(rv) {
+			if err := enc.EncodeElement(kv.value, el); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := enc.EncodeToken(el); err != nil {
+			return err
+		}
+		for i := 0; i < rv.Len(); i++ {
+			if err := enc.Encode(rv.Index(i).Interface()); err != nil {
+				return err
+			}
+		}
+		if err := enc.EncodeToken(el.End()); err != nil {
+			return err
+		}
+	}
+	return enc.EncodeToken(start.End())
+}
+
+func encodeFieldsMsgpack(enc *msgpack.Encoder, fields []orderedField) error {
+	if err := enc.EncodeMapLen(len(fields)); err != nil {
+		return err
+	}
+	for _, kv := range fields {
+		if err := enc.EncodeString(kv.key); err != nil {
+			return err
+		}
+		if err := enc.Encode(kv.value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
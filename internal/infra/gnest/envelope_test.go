@@ -0,0 +1,105 @@
+package gnest_test
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"blog/internal/infra/gnest"
+)
+
+type envelopeNote struct {
+	ID    int    `json:"id"`
+	Title string `json:"title"`
+}
+
+type denyAllGuard struct{}
+
+func (denyAllGuard) CanActivate(c *gin.Context) bool {
+	return gnest.Deny(c, gnest.Unauthorized("login required").WithCode("TOKEN_REQUIRED"))
+}
+
+func newEnvelopeApp(t *testing.T, envelope *gnest.EnvelopeInterceptor) *gnest.TestingApp {
+	t.Helper()
+	file := filepath.Join(t.TempDir(), "note.txt")
+	if err := os.WriteFile(file, []byte("file body"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	app := gnest.NewTestingApp()
+	app.UseGlobalInterceptors(envelope)
+	app.GET("/note", func() envelopeNote { return envelopeNote{ID: 1, Title: "hello"} })
+	app.GET("/missing", func() (envelopeNote, error) {
+		return envelopeNote{}, gnest.NotFound("note not found").WithCode("NOTE_NOT_FOUND")
+	})
+	app.GET("/guarded", func() string { return "secret" }, denyAllGuard{})
+	app.GET("/raw", func() envelopeNote { return envelopeNote{ID: 2} }, gnest.RawResponse())
+	app.GET("/raw-missing", func() (envelopeNote, error) {
+		return envelopeNote{}, gnest.NotFound("note not found")
+	}, gnest.RawResponse())
+	app.GET("/file", func() gnest.FileResult { return gnest.FileResult{FilePath: file} })
+	app.GET("/redirect", func() gnest.RedirectResult {
+		return gnest.RedirectResult{Code: http.StatusFound, Location: "/note"}
+	})
+	app.GET("/data", func() gnest.DataResult {
+		return gnest.DataResult{ContentType: "text/plain", Data: []byte("raw bytes")}
+	})
+	app.GET("/stream", func() gnest.StreamResult {
+		return gnest.StreamResult{Reader: strings.NewReader("streamed"), ContentType: "text/plain"}
+	})
+	if err := app.Compile(); err != nil {
+		t.Fatal(err)
+	}
+	return app
+}
+
+func TestEnvelopeInterceptor(t *testing.T) {
+	app := newEnvelopeApp(t, &gnest.EnvelopeInterceptor{})
+	client := app.Client(t)
+	cases := []struct {
+		name   string
+		path   string
+		status int
+		body   string
+	}{
+		{"wraps results", "/note", http.StatusOK, `{"code":200,"message":"success","data":{"id":1,"title":"hello"}}`},
+		{"wraps filter errors", "/missing", http.StatusNotFound, `{"code":404,"message":"note not found","data":{`},
+		{"wraps guard rejections", "/guarded", http.StatusUnauthorized, `{"code":401,"message":"login required","data":{`},
+		{"raw response opt-out", "/raw", http.StatusOK, `{"id":2,"title":""}`},
+		{"raw response errors", "/raw-missing", http.StatusNotFound, `{"statusCode":404`},
+		{"files pass through", "/file", http.StatusOK, "file body"},
+		{"redirects pass through", "/redirect", http.StatusFound, ""},
+		{"data passes through", "/data", http.StatusOK, "raw bytes"},
+		{"streams pass through", "/stream", http.StatusOK, "streamed"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			body := client.GET(tc.path).ExpectStatus(tc.status).Response().Body.String()
+			if !strings.HasPrefix(body, tc.body) {
+				t.Errorf("expected body starting with %s, got %s", tc.body, body)
+			}
+		})
+	}
+	client.GET("/missing").ExpectErrorCode("NOTE_NOT_FOUND")
+	client.GET("/guarded").ExpectErrorCode("TOKEN_REQUIRED")
+	client.GET("/redirect").ExpectHeader("Location", "/note")
+}
+
+func TestEnvelopeCustomKeys(t *testing.T) {
+	app := newEnvelopeApp(t, &gnest.EnvelopeInterceptor{Options: &gnest.EnvelopeOptions{
+		CodeKey: "status", MessageKey: "msg", DataKey: "result", SuccessMessage: "ok",
+	}})
+	client := app.Client(t)
+
+	if body := client.GET("/note").Response().Body.String(); body != `{"status":200,"msg":"ok","result":{"id":1,"title":"hello"}}` {
+		t.Errorf("unexpected success envelope: %s", body)
+	}
+	body := client.GET("/missing").ExpectErrorCode("NOTE_NOT_FOUND").Response().Body.String()
+	if !strings.HasPrefix(body, `{"status":404,"msg":"note not found","result":{`) {
+		t.Errorf("unexpected error envelope: %s", body)
+	}
+}
@@ -0,0 +1,308 @@
+package gnest
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"reflect"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// ==========================================
+// 事件总线 (Event Bus)
+// ==========================================
+
+// NamedEvent 可选：事件实现 EventName 以自定义主题 (如 "user.registered")，否则以类型名作为主题
+type NamedEvent interface {
+	EventName() string
+}
+
+// EventBusOptions 异步监听器的工作池配置
+type EventBusOptions struct {
+	Workers   int // 工作协程数，默认 runtime.NumCPU()
+	QueueSize int // 待处理事件的缓冲长度，队列满时 Publish 阻塞，默认 1024
+}
+
+// ListenerOption 监听器选项
+type ListenerOption func(l *listener)
+
+// Async 在工作池中异步执行监听器，Publish 不等待其完成
+func Async() ListenerOption {
+	return func(l *listener) { l.async = true }
+}
+
+type listener struct {
+	id      uint64
+	pattern []string     // 按 "." 拆分的主题模式，"*" 匹配一段，"**" 匹配剩余所有段
+	typ     reflect.Type // 非 nil 时只接收该类型的事件
+	name    string       // 日志中的监听器名
+	async   bool
+	call    func(ctx context.Context, topic string, event interface{}) error
+}
+
+type asyncEvent struct {
+	l     *listener
+	ctx   context.Context
+	topic string
+	event interface{}
+}
+
+// EventBus 进程内事件总线，作为内置 Provider 注册在根容器中。
+// 除 Subscribe 外，容器中的 Provider 按方法名约定自动订阅：
+//
+//	func (s *MailService) OnUserRegistered(ctx context.Context, e user.UserRegisteredEvent) error      // 同步
+//	func (s *MailService) OnUserRegisteredAsync(ctx context.Context, e user.UserRegisteredEvent) error // 异步
+//
+// 方法名为 On + 事件类型名 (可省略 Event 后缀)，可选 Async 后缀；监听器的 panic 与错误被隔离并记录日志
+type EventBus struct {
+	opts      EventBusOptions
+	mu        sync.RWMutex
+	listeners []*listener
+	nextID    uint64
+	queue     chan asyncEvent
+	wg        sync.WaitGroup // 未处理完的异步事件，Add 与 closed 的检查同在 mu 内
+	startOnce sync.Once
+	closed    bool
+	app       *GnestApp
+}
+
+// NewEventBus 创建事件总线
+func NewEventBus(opts EventBusOptions) *EventBus {
+	if opts.Workers <= 0 {
+		opts.Workers = runtime.NumCPU()
+	}
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = 1024
+	}
+	return &EventBus{opts: opts}
+}
+
+func (b *EventBus) setApp(app *GnestApp) { b.app = app }
+
+// Subscribe 订阅类型为 T 的事件，返回取消订阅函数
+func Subscribe[T any](b *EventBus, fn func(ctx context.Context, event T) error, opts ...ListenerOption) func() {
+	typ := typeOf[T]()
+	l := &listener{
+		pattern: strings.Split(topicOfType(typ), "."),
+		typ:     typ,
+		name:    fmt.Sprintf("func(%v)", typ),
+		call: func(ctx context.Context, _ string, event interface{}) error {
+			return fn(ctx, event.(T))
+		},
+	}
+	return b.subscribe(l, opts)
+}
+
+// SubscribeTopic 按主题模式订阅任意事件，如 "user.*" / "post.**"
+func (b *EventBus) SubscribeTopic(pattern string, fn func(ctx context.Context, topic string, event interface{}) error, opts ...ListenerOption) func() {
+	l := &listener{pattern: strings.Split(pattern, "."), name: "topic " + pattern, call: fn}
+	return b.subscribe(l, opts)
+}
+
+func (b *EventBus) subscribe(l *listener, opts []ListenerOption) func() {
+	for _, o := range opts {
+		o(l)
+	}
+	b.mu.Lock()
+	b.nextID++
+	l.id = b.nextID
+	b.listeners = append(b.listeners, l)
+	b.mu.Unlock()
+	return func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		for i, x := range b.listeners {
+			if x.id == l.id {
+				b.listeners = append(b.listeners[:i:i], b.listeners[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// Publish 发布事件：同步监听器按订阅顺序在当前协程执行，异步监听器投递到工作池。
+// b 为 nil 时不做任何事，便于未注入总线的场景 (如单独构造的 Service)
+func Publish[T any](ctx context.Context, b *EventBus, event T) {
+	if b == nil {
+		return
+	}
+	b.Publish(ctx, event)
+}
+
+// Publish 发布任意类型的事件，主题由 topicOf 决定
+func (b *EventBus) Publish(ctx context.Context, event interface{}) {
+	topic := topicOf(event)
+	segs := strings.Split(topic, ".")
+	typ := reflect.TypeOf(event)
+
+	b.mu.RLock()
+	var matched []*listener
+	for _, l := range b.listeners {
+		if (l.typ == nil || l.typ == typ) && matchTopic(l.pattern, segs) {
+			matched = append(matched, l)
+		}
+	}
+	closed := b.closed
+	if !closed {
+		// 在锁内登记待处理的异步事件，OnModuleDestroy 置 closed 后的 Wait 不会漏掉它们
+		for _, l := range matched {
+			if l.async {
+				b.wg.Add(1)
+			}
+		}
+	}
+	b.mu.RUnlock()
+
+	for _, l := range matched {
+		if !l.async || closed {
+			b.dispatch(ctx, l, topic, event)
+			continue
+		}
+		b.startOnce.Do(b.startWorkers)
+		// 异步监听器脱离请求的取消信号，但保留其中的值 (如请求 ID)
+		b.queue <- asyncEvent{l: l, ctx: context.WithoutCancel(ctx), topic: topic, event: event}
+	}
+}
+
+// dispatch 执行单个监听器，panic 与错误只记录不传播
+func (b *EventBus) dispatch(ctx context.Context, l *listener, topic string, event interface{}) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("[Gnest] event %q: listener %s panicked: %v", topic, l.name, r)
+		}
+	}()
+	if err := l.call(ctx, topic, event); err != nil {
+		log.Printf("[Gnest] event %q: listener %s failed: %v", topic, l.name, err)
+	}
+}
+
+func (b *EventBus) startWorkers() {
+	b.queue = make(chan asyncEvent, b.opts.QueueSize)
+	for i := 0; i < b.opts.Workers; i++ {
+		go func() {
+			for e := range b.queue {
+				b.dispatch(e.ctx, e.l, e.topic, e.event)
+				b.wg.Done()
+			}
+		}()
+	}
+}
+
+// OnModuleInit 扫描容器中 Provider 的 On<EventName> 方法并自动订阅
+func (b *EventBus) OnModuleInit(ctx context.Context) error {
+	if b.app == nil {
+		return nil
+	}
+	for _, inst := range b.app.container {
+		if inst == b {
+			continue
+		}
+		b.discover(inst)
+	}
+	return nil
+}
+
+// OnModuleDestroy 停止接收新的异步事件并等待队列处理完毕，随后关闭队列让工作协程退出；
+// 之后发布的事件改为同步执行
+func (b *EventBus) OnModuleDestroy(ctx context.Context) error {
+	b.mu.Lock()
+	b.closed = true
+	b.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		b.wg.Wait()
+		// closed 之后不会再启动工作池或投递事件，排空后即可安全关闭队列；超时返回后仍会在此收尾
+		b.startOnce.Do(func() {})
+		if b.queue != nil {
+			close(b.queue)
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("draining async listeners: %w", ctx.Err())
+	}
+}
+
+var contextType = reflect.TypeOf((*context.Context)(nil)).Elem()
+
+// discover 按 On<EventName>[Async](ctx context.Context, event T) [error] 的约定订阅
+func (b *EventBus) discover(inst interface{}) {
+	v := reflect.ValueOf(inst)
+	t := v.Type()
+	for i := 0; i < t.NumMethod(); i++ {
+		m := t.Method(i)
+		if !strings.HasPrefix(m.Name, "On") {
+			continue
+		}
+		mt := m.Type // 含接收者
+		if mt.NumIn() != 3 || mt.In(1) != contextType || mt.NumOut() > 1 || (mt.NumOut() == 1 && mt.Out(0) != errorType) {
+			continue
+		}
+		evType := mt.In(2)
+		name, async := strings.CutSuffix(strings.TrimPrefix(m.Name, "On"), "Async")
+		base := derefType(evType).Name()
+		if base == "" || (name != base && name+"Event" != base) {
+			continue
+		}
+		fn := v.Method(i)
+		l := &listener{
+			pattern: strings.Split(topicOfType(evType), "."),
+			typ:     evType,
+			name:    fmt.Sprintf("%T.%s", inst, m.Name),
+			call: func(ctx context.Context, _ string, event interface{}) error {
+				out := fn.Call([]reflect.Value{reflect.ValueOf(ctx), reflect.ValueOf(event)})
+				if len(out) == 1 && !out[0].IsNil() {
+					return out[0].Interface().(error)
+				}
+				return nil
+			},
+		}
+		var opts []ListenerOption
+		if async {
+			opts = append(opts, Async())
+		}
+		b.subscribe(l, opts)
+	}
+}
+
+// topicOf 事件主题：实现 NamedEvent 时取 EventName()，否则为类型名
+func topicOf(event interface{}) string {
+	if n, ok := event.(NamedEvent); ok {
+		return n.EventName()
+	}
+	return derefType(reflect.TypeOf(event)).Name()
+}
+
+var namedEventType = reflect.TypeOf((*NamedEvent)(nil)).Elem()
+
+// topicOfType 订阅时由事件类型求主题；在新建的零值上调用 EventName，
+// 指针类型的事件 (*Ev) 不会以 nil 接收者调用值方法
+func topicOfType(t reflect.Type) string {
+	if t.Implements(namedEventType) {
+		v := reflect.New(derefType(t))
+		if n, ok := v.Interface().(NamedEvent); ok {
+			return n.EventName()
+		}
+		return v.Elem().Interface().(NamedEvent).EventName()
+	}
+	return derefType(t).Name()
+}
+
+// matchTopic 逐段匹配主题，"*" 匹配一段，"**" 匹配剩余的任意段
+func matchTopic(pattern, topic []string) bool {
+	for i, p := range pattern {
+		if p == "**" {
+			return true
+		}
+		if i >= len(topic) || (p != "*" && p != topic[i]) {
+			return false
+		}
+	}
+	return len(pattern) == len(topic)
+}
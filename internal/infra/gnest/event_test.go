@@ -0,0 +1,71 @@
+package gnest_test
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"blog/internal/infra/gnest"
+)
+
+type pingEvent struct{ N int }
+
+func TestEventBusDestroyDrainsConcurrentPublishes(t *testing.T) {
+	b := gnest.NewEventBus(gnest.EventBusOptions{Workers: 2, QueueSize: 4})
+	var handled atomic.Int64
+	gnest.Subscribe(b, func(ctx context.Context, e pingEvent) error {
+		handled.Add(1)
+		return nil
+	}, gnest.Async())
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for n := 0; n < 50; n++ {
+				gnest.Publish(context.Background(), b, pingEvent{N: n})
+			}
+		}()
+	}
+	time.Sleep(time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := b.OnModuleDestroy(ctx); err != nil {
+		t.Fatal(err)
+	}
+	wg.Wait()
+	// 关闭前投递的事件已处理完，关闭后的事件同步执行，总数不丢失
+	if got := handled.Load(); got != 8*50 {
+		t.Errorf("expected 400 handled events, got %d", got)
+	}
+}
+
+type postPublished struct{ ID int }
+
+func (postPublished) EventName() string { return "post.published" }
+
+func TestSubscribePointerNamedEvent(t *testing.T) {
+	b := gnest.NewEventBus(gnest.EventBusOptions{})
+	var got []int
+	gnest.Subscribe(b, func(ctx context.Context, e *postPublished) error {
+		got = append(got, e.ID)
+		return nil
+	})
+	var topics []string
+	b.SubscribeTopic("post.*", func(ctx context.Context, topic string, _ interface{}) error {
+		topics = append(topics, topic)
+		return nil
+	})
+
+	gnest.Publish(context.Background(), b, &postPublished{ID: 7})
+	if len(got) != 1 || got[0] != 7 {
+		t.Errorf("expected the pointer listener to receive the event, got %v", got)
+	}
+	if len(topics) != 1 || topics[0] != "post.published" {
+		t.Errorf("expected the topic from EventName, got %v", topics)
+	}
+}
@@ -0,0 +1,224 @@
+package gnest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+)
+
+// ==========================================
+// HTTP 异常 (HttpException)
+// ==========================================
+
+// 内置错误码，客户端应依据 code 而不是 message 做分支判断
+const (
+	CodeBadRequest          = "BAD_REQUEST"
+	CodeValidationFailed    = "VALIDATION_FAILED"
+	CodeUnauthorized        = "UNAUTHORIZED"
+	CodeForbidden           = "FORBIDDEN"
+	CodeNotFound            = "NOT_FOUND"
+	CodeNotAcceptable       = "NOT_ACCEPTABLE"
+	CodeConflict            = "CONFLICT"
+	CodeUnprocessableEntity = "UNPROCESSABLE_ENTITY"
+	CodeTooManyRequests     = "TOO_MANY_REQUESTS"
+	CodeInternalServerError = "INTERNAL_SERVER_ERROR"
+	CodeGatewayTimeout      = "GATEWAY_TIMEOUT"
+	CodeClientClosed        = "CLIENT_CLOSED_REQUEST"
+)
+
+// StatusClientClosedRequest 客户端在响应前断开 (沿用 nginx 的 499)
+const StatusClientClosedRequest = 499
+
+// HttpException 携带 HTTP 状态码、业务错误码与错误详情的异常，
+// 控制器 / 服务直接返回即可，由 DefaultExceptionFilter 输出统一的错误响应
+type HttpException struct {
+	Status  int         // HTTP 状态码
+	Code    string      // 业务错误码
+	Message string      // 面向用户的错误信息
+	Details interface{} // 错误详情，如字段校验结果
+	cause   error
+}
+
+// ErrorBody 是所有异常统一的 JSON 响应结构
+type ErrorBody struct {
+	StatusCode int         `json:"statusCode"`
+	Code       string      `json:"code"`
+	Message    string      `json:"message"`
+	Error      string      `json:"error"`
+	Details    interface{} `json:"details,omitempty"`
+}
+
+// NewHttpException 创建自定义状态码的异常，code 为空时使用状态码对应的默认错误码
+func NewHttpException(status int, code, message string) *HttpException {
+	if code == "" {
+		code = defaultCode(status)
+	}
+	if message == "" {
+		message = http.StatusText(status)
+	}
+	return &HttpException{Status: status, Code: code, Message: message}
+}
+
+func BadRequest(message string) *HttpException {
+	return NewHttpException(http.StatusBadRequest, CodeBadRequest, message)
+}
+
+func Unauthorized(message string) *HttpException {
+	return NewHttpException(http.StatusUnauthorized, CodeUnauthorized, message)
+}
+
+func Forbidden(message string) *HttpException {
+	return NewHttpException(http.StatusForbidden, CodeForbidden, message)
+}
+
+func NotFound(message string) *HttpException {
+	return NewHttpException(http.StatusNotFound, CodeNotFound, message)
+}
+
+func NotAcceptable(message string) *HttpException {
+	return NewHttpException(http.StatusNotAcceptable, CodeNotAcceptable, message)
+}
+
+func Conflict(message string) *HttpException {
+	return NewHttpException(http.StatusConflict, CodeConflict, message)
+}
+
+func UnprocessableEntity(message string) *HttpException {
+	return NewHttpException(http.StatusUnprocessableEntity, CodeUnprocessableEntity, message)
+}
+
+func TooManyRequests(message string) *HttpException {
+	return NewHttpException(http.StatusTooManyRequests, CodeTooManyRequests, message)
+}
+
+func InternalServerError(message string) *HttpException {
+	return NewHttpException(http.StatusInternalServerError, CodeInternalServerError, message)
+}
+
+func GatewayTimeout(message string) *HttpException {
+	return NewHttpException(http.StatusGatewayTimeout, CodeGatewayTimeout, message)
+}
+
+func (e *HttpException) Error() string {
+	if e.cause != nil {
+		return fmt.Sprintf("%s: %v", e.Message, e.cause)
+	}
+	return e.Message
+}
+
+func (e *HttpException) Unwrap() error { return e.cause }
+
+// WithCode 替换业务错误码，如 NotFound("...").WithCode("USER_NOT_FOUND")
+func (e *HttpException) WithCode(code string) *HttpException {
+	e.Code = code
+	return e
+}
+
+// WithDetails 附加错误详情
+func (e *HttpException) WithDetails(details interface{}) *HttpException {
+	e.Details = details
+	return e
+}
+
+// WithCause 记录底层错误，便于 errors.Is / errors.As 追溯，不会输出给客户端
+func (e *HttpException) WithCause(err error) *HttpException {
+	e.cause = err
+	return e
+}
+
+// Body 生成统一的错误响应体
+func (e *HttpException) Body() ErrorBody {
+	return ErrorBody{
+		StatusCode: e.Status,
+		Code:       e.Code,
+		Message:    e.Message,
+		Error:      http.StatusText(e.Status),
+		Details:    e.Details,
+	}
+}
+
+// FieldError 是单个字段的校验失败信息
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Param   string `json:"param,omitempty"`
+	Message string `json:"message,omitempty"` // 按 Accept-Language 翻译后的提示
+}
+
+// ToHttpException 将任意错误翻译为 HttpException：
+// 已是 HttpException 的原样返回，gorm / validator / context 错误映射为对应状态码，其余为 500。
+// 500 只返回通用提示，原始错误 (可能含 SQL / 驱动信息) 仅作为 Cause 供日志使用
+func ToHttpException(err error) *HttpException {
+	var he *HttpException
+	if errors.As(err, &he) {
+		return he
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return NotFound("record not found").WithCause(err)
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return GatewayTimeout("request timed out").WithCause(err)
+	}
+	if errors.Is(err, context.Canceled) {
+		return NewHttpException(StatusClientClosedRequest, CodeClientClosed, "client closed request").WithCause(err)
+	}
+	var ve validator.ValidationErrors
+	if errors.As(err, &ve) {
+		details := make([]FieldError, 0, len(ve))
+		for _, fe := range ve {
+			details = append(details, FieldError{Field: fe.Field(), Rule: fe.Tag(), Param: fe.Param()})
+		}
+		return BadRequest("validation failed").WithCode(CodeValidationFailed).WithDetails(details).WithCause(err)
+	}
+	return InternalServerError("internal server error").WithCause(err)
+}
+
+func defaultCode(status int) string {
+	switch status {
+	case http.StatusBadRequest:
+		return CodeBadRequest
+	case http.StatusUnauthorized:
+		return CodeUnauthorized
+	case http.StatusForbidden:
+		return CodeForbidden
+	case http.StatusNotFound:
+		return CodeNotFound
+	case http.StatusNotAcceptable:
+		return CodeNotAcceptable
+	case http.StatusConflict:
+		return CodeConflict
+	case http.StatusUnprocessableEntity:
+		return CodeUnprocessableEntity
+	case http.StatusTooManyRequests:
+		return CodeTooManyRequests
+	case http.StatusInternalServerError:
+		return CodeInternalServerError
+	case http.StatusGatewayTimeout:
+		return CodeGatewayTimeout
+	case StatusClientClosedRequest:
+		return CodeClientClosed
+	}
+	return fmt.Sprintf("HTTP_%d", status)
+}
+
+// DefaultExceptionFilter 是框架内置的兜底过滤器，统一输出 ErrorBody
+type DefaultExceptionFilter struct{}
+
+var defaultFilter ExceptionFilter = &DefaultExceptionFilter{}
+
+func (f *DefaultExceptionFilter) Catch(c *gin.Context, err error) {
+	// 只有在业务没处理请求（没写入 Header）时才执行
+	if !c.IsAborted() {
+		he := ToHttpException(err)
+		if e := envelopeOf(c); e != nil {
+			c.AbortWithStatusJSON(he.Status, e.wrapError(he))
+			return
+		}
+		c.AbortWithStatusJSON(he.Status, he.Body())
+	}
+}
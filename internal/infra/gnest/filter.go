@@ -0,0 +1,188 @@
+package gnest
+
+import (
+	"reflect"
+	"sort"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ==========================================
+// 异常过滤器匹配 (Exception Filter Matching)
+// ==========================================
+
+// ExceptionCatcher 由过滤器可选实现，声明自己处理的错误：
+// typed nil (如 (*AuthError)(nil)) 按类型以 errors.As 匹配，
+// 非 nil 的哨兵错误 (如 gorm.ErrRecordNotFound) 以 errors.Is 匹配；
+// 未实现该接口的过滤器视为捕获所有错误
+type ExceptionCatcher interface {
+	Catches() []error
+}
+
+// CatchOf 供过滤器内嵌，声明捕获类型 E，对应 Nest 的 @Catch(E)：
+//
+//	type AuthExceptionFilter struct{ gnest.CatchOf[*AuthError] }
+//
+// E 也可以是接口类型，此时匹配实现了该接口的错误
+type CatchOf[E error] struct{}
+
+func (CatchOf[E]) Catches() []error {
+	var e E
+	return []error{e}
+}
+
+func (CatchOf[E]) catchType() reflect.Type {
+	return reflect.TypeOf((*E)(nil)).Elem()
+}
+
+// errorMatcher 返回错误链中第一个命中目标的深度，未命中返回 -1
+type errorMatcher func(err error) int
+
+// matchersOf 预编译过滤器声明的捕获目标，返回 nil 表示捕获所有错误
+func matchersOf(f ExceptionFilter) []errorMatcher {
+	if ct, ok := f.(interface{ catchType() reflect.Type }); ok {
+		return []errorMatcher{typeMatcher(ct.catchType())}
+	}
+	ec, ok := f.(ExceptionCatcher)
+	if !ok {
+		return nil
+	}
+	ms := make([]errorMatcher, 0)
+	for _, target := range ec.Catches() {
+		v := reflect.ValueOf(target)
+		switch {
+		case !v.IsValid():
+			// 接口类型的 nil 无法携带类型信息，忽略
+		case v.Kind() == reflect.Ptr && v.IsNil():
+			ms = append(ms, typeMatcher(v.Type()))
+		default:
+			ms = append(ms, sentinelMatcher(target))
+		}
+	}
+	return ms
+}
+
+func typeMatcher(t reflect.Type) errorMatcher {
+	return func(err error) int {
+		return chainDepth(err, func(e error) bool {
+			et := reflect.TypeOf(e)
+			if t.Kind() == reflect.Interface {
+				return et.Implements(t)
+			}
+			if et == t {
+				return true
+			}
+			// 兼容自定义 As 方法
+			if x, ok := e.(interface{ As(interface{}) bool }); ok {
+				return x.As(reflect.New(t).Interface())
+			}
+			return false
+		})
+	}
+}
+
+func sentinelMatcher(target error) errorMatcher {
+	comparable := reflect.TypeOf(target).Comparable()
+	return func(err error) int {
+		return chainDepth(err, func(e error) bool {
+			if comparable && e == target {
+				return true
+			}
+			if x, ok := e.(interface{ Is(error) bool }); ok {
+				return x.Is(target)
+			}
+			return false
+		})
+	}
+}
+
+// chainDepth 深度优先遍历错误链 (含 errors.Join)，返回最浅的命中深度
+func chainDepth(err error, match func(error) bool) int {
+	best := -1
+	var walk func(e error, depth int)
+	walk = func(e error, depth int) {
+		if e == nil || (best >= 0 && depth >= best) {
+			return
+		}
+		if match(e) {
+			best = depth
+			return
+		}
+		switch x := e.(type) {
+		case interface{ Unwrap() error }:
+			walk(x.Unwrap(), depth+1)
+		case interface{ Unwrap() []error }:
+			for _, inner := range x.Unwrap() {
+				walk(inner, depth+1)
+			}
+		}
+	}
+	walk(err, 0)
+	return best
+}
+
+// filterChain 是一条路由预合并后的过滤器链，顺序为 方法级 -> 组级 -> 全局
+type filterChain struct {
+	filters  []ExceptionFilter
+	matchers [][]errorMatcher // 与 filters 一一对应，nil 表示捕获所有错误
+}
+
+func newFilterChain(fs []ExceptionFilter) *filterChain {
+	fc := &filterChain{filters: fs, matchers: make([][]errorMatcher, len(fs))}
+	for i, f := range fs {
+		fc.matchers[i] = matchersOf(f)
+	}
+	return fc
+}
+
+// candidates 按“最具体优先”排序本次错误要经过的过滤器：
+// 声明了类型且命中的过滤器优先，命中位置越靠近错误本身越具体，同等具体时方法级优先；
+// 其后是捕获所有错误的过滤器，保持 方法级 -> 组级 -> 全局 的顺序
+func (fc *filterChain) candidates(err error) []ExceptionFilter {
+	type hit struct {
+		depth int
+		index int
+	}
+	var typed []hit
+	var catchAll []ExceptionFilter
+	for i, ms := range fc.matchers {
+		if ms == nil {
+			catchAll = append(catchAll, fc.filters[i])
+			continue
+		}
+		depth := -1
+		for _, m := range ms {
+			if d := m(err); d >= 0 && (depth < 0 || d < depth) {
+				depth = d
+			}
+		}
+		if depth >= 0 {
+			typed = append(typed, hit{depth: depth, index: i})
+		}
+	}
+	sort.SliceStable(typed, func(a, b int) bool { return typed[a].depth < typed[b].depth })
+
+	out := make([]ExceptionFilter, 0, len(typed)+len(catchAll))
+	for _, h := range typed {
+		out = append(out, fc.filters[h.index])
+	}
+	return append(out, catchAll...)
+}
+
+func (rg *RouterGroup) processError(c *gin.Context, err error, fc *filterChain) {
+	// 消息处理器的错误同时交还给传输层 (如投递到死信队列)
+	if mc := messageContextOf(c); mc != nil && mc.err == nil {
+		mc.err = err
+	}
+	fc.catch(c, err)
+}
+
+// catch 依次交给候选过滤器，直到某个过滤器写出响应 (Abort)
+func (fc *filterChain) catch(c *gin.Context, err error) {
+	for _, f := range fc.candidates(err) {
+		f.Catch(c, err)
+		if c.IsAborted() {
+			return
+		}
+	}
+}
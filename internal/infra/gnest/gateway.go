@@ -0,0 +1,696 @@
+package gnest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+)
+
+// ==========================================
+// WebSocket 网关 (Gateway)
+// ==========================================
+
+// Gateway 对应 Nest 的 @WebSocketGateway：网关本身是一个 Provider，
+// 在 Messages 中按事件名注册消息处理器，通过 app.UseGateway 挂载到路径上
+type Gateway interface {
+	Messages(s *GatewayServer)
+}
+
+// OnGatewayConnect 客户端完成握手后调用，返回错误会关闭连接
+type OnGatewayConnect interface{ OnConnect(client *Socket) error }
+
+// OnGatewayDisconnect 客户端断开后调用
+type OnGatewayDisconnect interface{ OnDisconnect(client *Socket) }
+
+// WsMessage 是客户端与服务端之间的消息格式：
+// 客户端 {"event":"chat.send","data":{...},"id":"1"}，id 不为空时服务端以同一 id 回复处理结果
+type WsMessage struct {
+	Event string          `json:"event"`
+	Data  json.RawMessage `json:"data,omitempty"`
+	ID    string          `json:"id,omitempty"`
+}
+
+// WsExceptionEvent 处理器返回错误时推送给客户端的事件名，数据为 ErrorBody
+const WsExceptionEvent = "exception"
+
+// BroadcastMessage 是经由适配器分发的广播
+type BroadcastMessage struct {
+	Path   string          `json:"path"`   // 网关路径，同一适配器可被多个网关共用
+	Room   string          `json:"room"`   // 为空表示广播给所有连接
+	Except string          `json:"except"` // 排除的连接 ID (通常是发送者)
+	Event  string          `json:"event"`
+	Data   json.RawMessage `json:"data"`
+}
+
+// WsAdapter 广播适配器：Publish 发布的广播会投递给所有实例 (含本实例) 的订阅者，
+// 多实例部署时可基于 Redis Pub/Sub 等实现；作为 UseGateway 的增强器传入
+type WsAdapter interface {
+	Publish(msg BroadcastMessage) error
+	Subscribe(fn func(msg BroadcastMessage)) (unsubscribe func(), err error)
+}
+
+// MemoryAdapter 进程内适配器，未指定适配器时默认使用；
+// 多个网关服务共用同一个 MemoryAdapter 即可在测试中模拟多实例
+type MemoryAdapter struct {
+	mu   sync.RWMutex
+	subs map[int]func(BroadcastMessage)
+	next int
+}
+
+func NewMemoryAdapter() *MemoryAdapter {
+	return &MemoryAdapter{subs: make(map[int]func(BroadcastMessage))}
+}
+
+func (a *MemoryAdapter) Publish(msg BroadcastMessage) error {
+	a.mu.RLock()
+	subs := make([]func(BroadcastMessage), 0, len(a.subs))
+	for _, fn := range a.subs {
+		subs = append(subs, fn)
+	}
+	a.mu.RUnlock()
+	for _, fn := range subs {
+		fn(msg)
+	}
+	return nil
+}
+
+func (a *MemoryAdapter) Subscribe(fn func(BroadcastMessage)) (func(), error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	id := a.next
+	a.next++
+	a.subs[id] = fn
+	return func() {
+		a.mu.Lock()
+		delete(a.subs, id)
+		a.mu.Unlock()
+	}, nil
+}
+
+// GatewayServer 一个挂载路径上的网关服务，管理连接、房间与消息处理器
+type GatewayServer struct {
+	app      *GnestApp
+	path     string
+	gateway  interface{}
+	module   *moduleRef
+	guards   []CanActivate
+	filters  *filterChain
+	adapter  WsAdapter
+	unsub    func() // 退订广播适配器，关闭时调用
+	upgrader *websocket.Upgrader
+	handlers map[string]*wsHandler
+	errors   *gin.Engine // 执行异常过滤器链的内部引擎，见 catch
+
+	mu      sync.RWMutex
+	sockets map[string]*Socket
+	rooms   map[string]map[string]*Socket
+}
+
+type wsHandler struct {
+	fn        reflect.Value
+	resolvers []wsResolver
+}
+
+type wsResolver func(s *Socket, data json.RawMessage) (reflect.Value, error)
+
+// gatewayMount 记录 UseGateway 的声明，Init 时挂载
+type gatewayMount struct {
+	path      string
+	gateway   interface{}
+	enhancers []interface{}
+}
+
+// UseGateway 将网关挂载到 path，网关未注册为 Provider 时自动注册到根容器。
+// 增强器支持 CanActivate (握手阶段执行)、WsAdapter 与 *websocket.Upgrader
+func (app *GnestApp) UseGateway(path string, gateway interface{}, enhancers ...interface{}) *GnestApp {
+	app.gateways = append(app.gateways, gatewayMount{path: path, gateway: gateway, enhancers: enhancers})
+	return app
+}
+
+// findProvider 在根容器与所有模块中查找 Provider
+func (app *GnestApp) findProvider(tok token) (*providerDef, bool) {
+	if def, ok := app.providers[tok]; ok {
+		return def, true
+	}
+	for _, ref := range app.modules {
+		if def, ok := ref.providers[tok]; ok {
+			return def, true
+		}
+	}
+	return nil, false
+}
+
+func (app *GnestApp) mountGateway(m gatewayMount) error {
+	tok := tokenOf(m.gateway)
+	def, ok := app.findProvider(tok)
+	if !ok {
+		var err error
+		if def, err = newProviderDef(m.gateway, nil); err != nil {
+			return fmt.Errorf("gateway %s: %w", m.path, err)
+		}
+		app.providers[def.tok] = def
+		if err := app.resolveAll([]*providerDef{def}); err != nil {
+			return fmt.Errorf("gateway %s: %w", m.path, err)
+		}
+	}
+	if app.lifetime(def) != Singleton {
+		return fmt.Errorf("gateway %s: %v must be a singleton", m.path, def.tok)
+	}
+	inst, err := app.resolve(def, nil, nil)
+	if err != nil {
+		return fmt.Errorf("gateway %s: %w", m.path, err)
+	}
+	gw, ok := inst.Interface().(Gateway)
+	if !ok {
+		return fmt.Errorf("gateway %s: %v does not implement gnest.Gateway", m.path, def.tok)
+	}
+
+	s := &GatewayServer{
+		app:      app,
+		path:     m.path,
+		gateway:  gw,
+		module:   def.module,
+		upgrader: &websocket.Upgrader{},
+		handlers: make(map[string]*wsHandler),
+		sockets:  make(map[string]*Socket),
+		rooms:    make(map[string]map[string]*Socket),
+	}
+	var filters []ExceptionFilter
+	for _, e := range m.enhancers {
+		switch v := e.(type) {
+		case WsAdapter:
+			s.adapter = v
+		case *websocket.Upgrader:
+			s.upgrader = v
+		case CanActivate:
+			s.guards = append(s.guards, v)
+		case ExceptionFilter:
+			filters = append(filters, v)
+		default:
+			return fmt.Errorf("gateway %s: unsupported enhancer %T", m.path, v)
+		}
+	}
+	s.filters = newFilterChain(concat(filters, app.globalFilters, []ExceptionFilter{defaultFilter}))
+	// 内部引擎不注册路由，握手请求以原始方法与路径进入 NoRoute，由过滤器链写出响应
+	s.errors = gin.New()
+	s.errors.ContextWithFallback = app.Engine.ContextWithFallback
+	s.errors.NoRoute(s.catchHandler)
+	if s.adapter == nil {
+		s.adapter = NewMemoryAdapter()
+	}
+	unsub, err := s.adapter.Subscribe(s.deliver)
+	if err != nil {
+		return fmt.Errorf("gateway %s: subscribe adapter: %w", m.path, err)
+	}
+	s.unsub = unsub
+	app.gatewayServers = append(app.gatewayServers, s)
+
+	var regErr error
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				regErr = fmt.Errorf("gateway %s: %v", m.path, r)
+			}
+		}()
+		gw.Messages(s)
+	}()
+	if regErr != nil {
+		return regErr
+	}
+	app.Engine.GET(m.path, s.handshake)
+	return nil
+}
+
+// On 注册事件处理器。处理函数的参数可以是 *Socket、握手时的 *gin.Context、
+// Provider 以及 DTO 结构体指针 (由消息 data 解码并校验)、json.RawMessage；
+// 返回值 (结果) / (结果, error) / error，结果作为同名事件回复
+func (s *GatewayServer) On(event string, handler interface{}) {
+	hVal := reflect.ValueOf(handler)
+	hTyp := hVal.Type()
+	if hTyp.Kind() != reflect.Func {
+		panic(fmt.Sprintf("gnest: handler for event %q must be a function", event))
+	}
+	if _, dup := s.handlers[event]; dup {
+		panic(fmt.Sprintf("gnest: duplicate handler for event %q", event))
+	}
+	h := &wsHandler{fn: hVal, resolvers: make([]wsResolver, hTyp.NumIn())}
+	for i := 0; i < hTyp.NumIn(); i++ {
+		h.resolvers[i] = s.makeResolver(hTyp.In(i))
+	}
+	s.handlers[event] = h
+}
+
+var (
+	socketType     = reflect.TypeOf(&Socket{})
+	ginContextType = reflect.TypeOf(&gin.Context{})
+	rawMessageType = reflect.TypeOf(json.RawMessage{})
+)
+
+func (s *GatewayServer) makeResolver(t reflect.Type) wsResolver {
+	switch {
+	case t == socketType:
+		return func(sock *Socket, _ json.RawMessage) (reflect.Value, error) { return reflect.ValueOf(sock), nil }
+	case t == ginContextType:
+		return func(sock *Socket, _ json.RawMessage) (reflect.Value, error) { return reflect.ValueOf(sock.ctx), nil }
+	case t == rawMessageType:
+		return func(_ *Socket, data json.RawMessage) (reflect.Value, error) { return reflect.ValueOf(data), nil }
+	}
+	if def, ok := s.app.lookup(s.module, token{typ: t}); ok {
+		return func(_ *Socket, _ json.RawMessage) (reflect.Value, error) { return s.app.resolveShared(def, nil) }
+	}
+	if t.Kind() == reflect.Ptr && t.Elem().Kind() == reflect.Struct {
+		st := t.Elem()
+		return func(sock *Socket, data json.RawMessage) (reflect.Value, error) {
+			obj := reflect.New(st)
+			if len(data) > 0 {
+				if err := json.Unmarshal(data, obj.Interface()); err != nil {
+					return reflect.Value{}, BadRequest("malformed message payload").WithCause(err)
+				}
+			}
+			// 与 HTTP 一致：binding tag 与 validate tag 均参与校验
+			for _, v := range s.app.validators() {
+				if err := v.Struct(obj.Interface()); err != nil {
+					return reflect.Value{}, s.app.validationException(sock.ctx, v, st, err)
+				}
+			}
+			return obj, nil
+		}
+	}
+	panic(fmt.Sprintf("gnest: unsupported gateway handler parameter %v", t))
+}
+
+// handshake 执行守卫后升级连接
+func (s *GatewayServer) handshake(c *gin.Context) {
+	for _, g := range s.app.overrideGuards(concat(s.app.globalGuards, s.guards)) {
+		if !g.CanActivate(c) {
+			if !c.IsAborted() {
+				s.processError(c, guardError(c))
+			}
+			return
+		}
+	}
+	conn, err := s.upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		return // Upgrade 已写出错误响应
+	}
+	// 握手请求结束后 gin.Context 会被复用，保留一份副本供处理器读取
+	sock := &Socket{
+		ID:     uuid.New().String(),
+		server: s,
+		conn:   conn,
+		ctx:    c.Copy(),
+		rooms:  make(map[string]bool),
+		send:   make(chan []byte, 64),
+		done:   make(chan struct{}),
+	}
+	s.mu.Lock()
+	s.sockets[sock.ID] = sock
+	s.mu.Unlock()
+
+	// OnConnect 中 Emit 的消息先进入发送队列，连接被接受后再由 writeLoop 写出
+	if h, ok := s.gateway.(OnGatewayConnect); ok {
+		if err := h.OnConnect(sock); err != nil {
+			s.reject(sock, err)
+			return
+		}
+	}
+	go sock.writeLoop()
+	sock.readLoop()
+}
+
+// closeGateways 退订所有网关的广播适配器，在 OnModuleDestroy 之前执行
+func (app *GnestApp) closeGateways() {
+	for _, s := range app.gatewayServers {
+		if s.unsub != nil {
+			s.unsub()
+			s.unsub = nil
+		}
+	}
+}
+
+// reject 拒绝连接：直接写出 exception 事件并关闭，不触发 OnDisconnect
+func (s *GatewayServer) reject(sock *Socket, err error) {
+	raw := s.catch(sock, err)
+	if frame, err := json.Marshal(WsMessage{Event: WsExceptionEvent, Data: raw}); err == nil {
+		sock.conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+		sock.conn.WriteMessage(websocket.TextMessage, frame)
+	}
+	sock.conn.Close()
+	sock.closed.Store(true)
+	s.mu.Lock()
+	s.removeLocked(sock)
+	s.mu.Unlock()
+	close(sock.done)
+}
+
+// catch 与 HTTP 一致地将错误交给网关的异常过滤器链 (网关级 -> 全局 -> 内置)：
+// 过滤器在内部引擎为握手请求创建的 gin.Context 上写出响应，写出的 JSON 即 exception 事件的数据；
+// 过滤器未写出 JSON 时回退为统一的 ErrorBody
+func (s *GatewayServer) catch(sock *Socket, err error) json.RawMessage {
+	gc := &gatewayCatch{err: err, keys: sock.ctx.Keys}
+	req := sock.ctx.Request.WithContext(context.WithValue(sock.ctx.Request.Context(), gatewayCatchKey{}, gc))
+	w := newBufferedResponse()
+	s.errors.ServeHTTP(w, req)
+	if body := bytes.TrimSpace(w.body.Bytes()); len(body) > 0 && json.Valid(body) {
+		return body
+	}
+	raw, _ := json.Marshal(ToHttpException(err).Body())
+	return raw
+}
+
+// gatewayCatch 随内部请求传递待处理的错误与握手请求上的上下文值
+type gatewayCatch struct {
+	err  error
+	keys map[string]any
+}
+
+type gatewayCatchKey struct{}
+
+func (s *GatewayServer) catchHandler(c *gin.Context) {
+	gc := c.Request.Context().Value(gatewayCatchKey{}).(*gatewayCatch)
+	for k, v := range gc.keys {
+		c.Set(k, v)
+	}
+	s.processError(c, gc.err)
+}
+
+func (s *GatewayServer) processError(c *gin.Context, err error) {
+	for _, f := range s.filters.candidates(err) {
+		f.Catch(c, err)
+		if c.IsAborted() {
+			return
+		}
+	}
+}
+
+func (s *GatewayServer) disconnect(sock *Socket) {
+	if !sock.closed.CompareAndSwap(false, true) {
+		return
+	}
+	s.mu.Lock()
+	s.removeLocked(sock)
+	s.mu.Unlock()
+	close(sock.done)
+	if h, ok := s.gateway.(OnGatewayDisconnect); ok {
+		h.OnDisconnect(sock)
+	}
+}
+
+func (s *GatewayServer) removeLocked(sock *Socket) {
+	delete(s.sockets, sock.ID)
+	for room := range sock.rooms {
+		if members := s.rooms[room]; members != nil {
+			delete(members, sock.ID)
+			if len(members) == 0 {
+				delete(s.rooms, room)
+			}
+		}
+	}
+}
+
+func (s *GatewayServer) dispatch(sock *Socket, msg WsMessage) {
+	h, ok := s.handlers[msg.Event]
+	if !ok {
+		sock.emitError(msg.ID, NotFound(fmt.Sprintf("no handler for event %q", msg.Event)))
+		return
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err, ok := r.(error)
+			if !ok {
+				err = fmt.Errorf("%v", r)
+			}
+			sock.emitError(msg.ID, err)
+		}
+	}()
+	args := make([]reflect.Value, len(h.resolvers))
+	for i, resolve := range h.resolvers {
+		v, err := resolve(sock, msg.Data)
+		if err != nil {
+			sock.emitError(msg.ID, err)
+			return
+		}
+		args[i] = v
+	}
+	var result interface{}
+	for _, out := range h.fn.Call(args) {
+		if out.Type() == errorType {
+			if !out.IsNil() {
+				sock.emitError(msg.ID, out.Interface().(error))
+				return
+			}
+			continue
+		}
+		result = out.Interface()
+	}
+	if msg.ID != "" || result != nil {
+		sock.reply(msg.Event, msg.ID, result)
+	}
+}
+
+// deliver 接收适配器投递的广播并发给本实例的连接
+func (s *GatewayServer) deliver(msg BroadcastMessage) {
+	if msg.Path != s.path {
+		return
+	}
+	frame, err := json.Marshal(WsMessage{Event: msg.Event, Data: msg.Data})
+	if err != nil {
+		return
+	}
+	s.mu.RLock()
+	targets := s.sockets
+	if msg.Room != "" {
+		targets = s.rooms[msg.Room]
+	}
+	list := make([]*Socket, 0, len(targets))
+	for id, sock := range targets {
+		if id != msg.Except {
+			list = append(list, sock)
+		}
+	}
+	s.mu.RUnlock()
+	for _, sock := range list {
+		sock.write(frame)
+	}
+}
+
+// Broadcast 向本网关的所有连接 (跨实例) 推送事件
+func (s *GatewayServer) Broadcast(event string, data interface{}) error {
+	return s.publish("", "", event, data)
+}
+
+// To 选择房间，返回的 Emitter 会把事件推送给房间内的所有连接 (跨实例)
+func (s *GatewayServer) To(room string) *Emitter {
+	return &Emitter{server: s, room: room}
+}
+
+func (s *GatewayServer) publish(room, except, event string, data interface{}) error {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	return s.adapter.Publish(BroadcastMessage{Path: s.path, Room: room, Except: except, Event: event, Data: raw})
+}
+
+// Sockets 返回本实例上的连接数
+func (s *GatewayServer) Sockets() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.sockets)
+}
+
+// Emitter 指向某个房间的广播器
+type Emitter struct {
+	server *GatewayServer
+	room   string
+	except string
+}
+
+func (e *Emitter) Emit(event string, data interface{}) error {
+	return e.server.publish(e.room, e.except, event, data)
+}
+
+// ==========================================
+// Socket 连接
+// ==========================================
+
+// Socket 一个客户端连接
+type Socket struct {
+	ID     string
+	server *GatewayServer
+	conn   *websocket.Conn
+	ctx    *gin.Context // 握手请求的副本，可读取 Header、Query 及守卫写入的值
+	rooms  map[string]bool
+	send   chan []byte
+	done   chan struct{}
+	closed atomic.Bool
+	values sync.Map
+}
+
+// Context 握手请求的上下文
+func (sock *Socket) Context() *gin.Context { return sock.ctx }
+
+// Set / Get 在连接上保存自定义数据，如鉴权后的用户
+func (sock *Socket) Set(key string, value interface{}) { sock.values.Store(key, value) }
+func (sock *Socket) Get(key string) (interface{}, bool) {
+	return sock.values.Load(key)
+}
+
+// Emit 仅向当前连接推送事件
+func (sock *Socket) Emit(event string, data interface{}) error {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	frame, err := json.Marshal(WsMessage{Event: event, Data: raw})
+	if err != nil {
+		return err
+	}
+	if !sock.write(frame) {
+		return errClientGone
+	}
+	return nil
+}
+
+// Join 加入房间
+func (sock *Socket) Join(room string) {
+	s := sock.server
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if sock.closed.Load() {
+		return
+	}
+	if s.rooms[room] == nil {
+		s.rooms[room] = make(map[string]*Socket)
+	}
+	s.rooms[room][sock.ID] = sock
+	sock.rooms[room] = true
+}
+
+// Leave 离开房间
+func (sock *Socket) Leave(room string) {
+	s := sock.server
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if members := s.rooms[room]; members != nil {
+		delete(members, sock.ID)
+		if len(members) == 0 {
+			delete(s.rooms, room)
+		}
+	}
+	delete(sock.rooms, room)
+}
+
+// Rooms 当前连接所在的房间
+func (sock *Socket) Rooms() []string {
+	sock.server.mu.RLock()
+	defer sock.server.mu.RUnlock()
+	rooms := make([]string, 0, len(sock.rooms))
+	for r := range sock.rooms {
+		rooms = append(rooms, r)
+	}
+	return rooms
+}
+
+// To 向房间内除自己以外的连接推送事件
+func (sock *Socket) To(room string) *Emitter {
+	return &Emitter{server: sock.server, room: room, except: sock.ID}
+}
+
+// Close 主动断开连接
+func (sock *Socket) Close() error {
+	return sock.conn.Close()
+}
+
+func (sock *Socket) write(frame []byte) bool {
+	select {
+	case <-sock.done:
+		return false
+	case sock.send <- frame:
+		return true
+	default:
+		// 发送队列已满说明客户端消费过慢，断开以保护服务端
+		sock.conn.Close()
+		return false
+	}
+}
+
+func (sock *Socket) reply(event, id string, data interface{}) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		sock.emitError(id, err)
+		return
+	}
+	if frame, err := json.Marshal(WsMessage{Event: event, Data: raw, ID: id}); err == nil {
+		sock.write(frame)
+	}
+}
+
+// emitError 经过网关的异常过滤器链，以 exception 事件回复过滤器输出的错误体
+func (sock *Socket) emitError(id string, err error) {
+	raw := sock.server.catch(sock, err)
+	if frame, err := json.Marshal(WsMessage{Event: WsExceptionEvent, Data: raw, ID: id}); err == nil {
+		sock.write(frame)
+	}
+}
+
+const (
+	wsWriteWait  = 10 * time.Second
+	wsPongWait   = 60 * time.Second
+	wsPingPeriod = wsPongWait * 9 / 10
+)
+
+func (sock *Socket) readLoop() {
+	defer func() {
+		sock.conn.Close()
+		sock.server.disconnect(sock)
+	}()
+	sock.conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	sock.conn.SetPongHandler(func(string) error {
+		return sock.conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	})
+	for {
+		_, data, err := sock.conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		var msg WsMessage
+		if err := json.Unmarshal(data, &msg); err != nil || msg.Event == "" {
+			sock.emitError(msg.ID, BadRequest("malformed message"))
+			continue
+		}
+		sock.server.dispatch(sock, msg)
+	}
+}
+
+func (sock *Socket) writeLoop() {
+	ticker := time.NewTicker(wsPingPeriod)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-sock.done:
+			return
+		case frame := <-sock.send:
+			sock.conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+			if err := sock.conn.WriteMessage(websocket.TextMessage, frame); err != nil {
+				sock.conn.Close()
+				return
+			}
+		case <-ticker.C:
+			sock.conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+			if err := sock.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				sock.conn.Close()
+				return
+			}
+		}
+	}
+}
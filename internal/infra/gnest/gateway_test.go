@@ -0,0 +1,100 @@
+package gnest_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"blog/internal/infra/gnest"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+var errTeapot = errors.New("teapot")
+
+type teapotGateway struct{}
+
+func (g *teapotGateway) Messages(s *gnest.GatewayServer) {
+	s.On("brew", func() error { return errTeapot })
+}
+
+// teapotFilter 只处理 errTeapot，其余错误交给内置过滤器
+type teapotFilter struct{}
+
+func (teapotFilter) Catches() []error { return []error{errTeapot} }
+func (teapotFilter) Catch(c *gin.Context, err error) {
+	c.AbortWithStatusJSON(http.StatusTeapot, gin.H{"code": "TEAPOT"})
+}
+
+// countingAdapter 记录仍然有效的订阅数
+type countingAdapter struct {
+	*gnest.MemoryAdapter
+	active atomic.Int32
+}
+
+func (a *countingAdapter) Subscribe(fn func(gnest.BroadcastMessage)) (func(), error) {
+	unsub, err := a.MemoryAdapter.Subscribe(fn)
+	if err != nil {
+		return nil, err
+	}
+	a.active.Add(1)
+	return func() {
+		a.active.Add(-1)
+		unsub()
+	}, nil
+}
+
+func TestGatewayErrorsUseFilters(t *testing.T) {
+	adapter := &countingAdapter{MemoryAdapter: gnest.NewMemoryAdapter()}
+	app := gnest.NewTestingApp()
+	app.UseGateway("/ws", &teapotGateway{}, teapotFilter{}, adapter)
+	if err := app.Compile(); err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(app.Engine)
+	defer srv.Close()
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http")+"/ws", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	expect := func(event, want string) {
+		t.Helper()
+		if err := conn.WriteJSON(gnest.WsMessage{Event: event, ID: event}); err != nil {
+			t.Fatal(err)
+		}
+		conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+		var msg gnest.WsMessage
+		if err := conn.ReadJSON(&msg); err != nil {
+			t.Fatal(err)
+		}
+		var body struct {
+			Code string `json:"code"`
+		}
+		_ = json.Unmarshal(msg.Data, &body)
+		if msg.Event != gnest.WsExceptionEvent || msg.ID != event || body.Code != want {
+			t.Errorf("%s: expected exception with code %q, got %s %s", event, want, msg.Event, msg.Data)
+		}
+	}
+	expect("brew", "TEAPOT")
+	expect("missing", gnest.CodeNotFound)
+
+	if n := adapter.active.Load(); n != 1 {
+		t.Fatalf("expected one adapter subscription, got %d", n)
+	}
+	if err := app.Shutdown(context.Background(), "test"); err != nil {
+		t.Fatal(err)
+	}
+	if n := adapter.active.Load(); n != 0 {
+		t.Errorf("expected the adapter subscription to be released on shutdown, got %d", n)
+	}
+}
@@ -1,7 +1,9 @@
 package gnest
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"html/template"
 	"log"
@@ -9,10 +11,12 @@ import (
 	"os"
 	"os/signal"
 	"reflect"
+	"strings"
+	"sync"
 	"syscall"
-	"time"
 
 	"github.com/gin-gonic/gin"
+	ut "github.com/go-playground/universal-translator"
 	"github.com/go-playground/validator/v10"
 )
 
@@ -27,25 +31,33 @@ type handlerMetadata struct {
 	resolvers []argumentResolver
 }
 
-// DefaultExceptionFilter 是框架内置的兜底过滤器
-type DefaultExceptionFilter struct{}
+type CanActivate interface{ CanActivate(ctx *gin.Context) bool }
+
+const guardErrorCtxKey = "gnest.guardError"
 
-func (f *DefaultExceptionFilter) Catch(c *gin.Context, err error) {
-	// 只有在业务没处理请求（没写入 Header）时才执行
-	if !c.IsAborted() {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"statusCode": 500,
-			"message":    err.Error(),
-			"error":      "Internal Server Error",
-		})
-		c.Abort()
+// Deny 供守卫拒绝请求时指定返回的错误 (默认 403)，对应 Nest 守卫中抛出的异常：
+//
+//	return gnest.Deny(c, gnest.TooManyRequests("Too Many Requests"))
+func Deny(c *gin.Context, err error) bool {
+	c.Set(guardErrorCtxKey, err)
+	return false
+}
+
+// guardError 守卫通过 Deny 指定的错误，未指定时为 403
+func guardError(c *gin.Context) error {
+	v, _ := c.Get(guardErrorCtxKey)
+	if err, ok := v.(error); ok {
+		return err
 	}
+	return Forbidden("Forbidden resource")
 }
 
-type CanActivate interface{ CanActivate(ctx *gin.Context) bool }
 type NestInterceptor interface {
 	Intercept(ctx *gin.Context, next func() interface{}) interface{}
 }
+
+// requestPreparer 由拦截器可选实现，在守卫之前为请求做准备 (如响应信封)
+type requestPreparer interface{ prepareRequest(c *gin.Context) }
 type PipeTransform interface {
 	Transform(value interface{}, targetType reflect.Type) (interface{}, error)
 }
@@ -53,13 +65,26 @@ type ExceptionFilter interface {
 	Catch(ctx *gin.Context, err error)
 }
 
-// --- 初始化阶段 ---
-type OnModuleInit interface{ OnModuleInit() }                     // 所有依赖注入完成
-type OnApplicationBootstrap interface{ OnApplicationBootstrap() } // 应用准备好接收请求前
-// --- 终止阶段 ---
-type OnModuleDestroy interface{ OnModuleDestroy() }                               // 收到信号，准备关闭（清理定时器等）
-type BeforeApplicationShutdown interface{ BeforeApplicationShutdown(sig string) } // 停止接收连接，关闭 DB 前
-type OnApplicationShutdown interface{ OnApplicationShutdown() }                   // 所有资源已释放，进程即将退出
+// --- 初始化阶段 (按依赖顺序执行，失败则中止启动) ---
+type OnModuleInit interface {
+	OnModuleInit(ctx context.Context) error
+} // 所有依赖注入完成
+type OnApplicationBootstrap interface {
+	OnApplicationBootstrap(ctx context.Context) error
+} // 应用准备好接收请求前
+// --- 终止阶段 (按依赖逆序执行，错误仅记录) ---
+type OnModuleDestroy interface {
+	OnModuleDestroy(ctx context.Context) error
+} // 收到信号，准备关闭（清理定时器等）
+type BeforeApplicationShutdown interface {
+	BeforeApplicationShutdown(ctx context.Context, sig string) error // 停止接收连接，关闭 DB 前
+}
+type OnApplicationShutdown interface {
+	OnApplicationShutdown(ctx context.Context, sig string) error // 所有连接已关闭，释放 DB / Redis / Kafka 等资源
+}
+
+// --- 请求阶段 ---
+type OnRequestDestroy interface{ OnRequestDestroy() } // 请求 / 瞬时作用域实例在请求结束时销毁
 
 // Render 用于渲染 HTML 模板
 type Render struct {
@@ -90,69 +115,78 @@ type FileResult struct {
 // ==========================================
 
 type GnestApp struct {
-	Engine             *gin.Engine
-	providers          map[reflect.Type]reflect.Value
-	container          []interface{} // 用于扫描生命周期钩子
-	globalGuards       []CanActivate
-	globalInterceptors []NestInterceptor
-	globalPipes        []PipeTransform
-	globalFilters      []ExceptionFilter
-	customDecorators   map[reflect.Type]func(c *gin.Context) interface{} // 补回：自定义参数装饰器
-	validate           *validator.Validate                               // 增加：内置校验器
+	Engine               *gin.Engine
+	providers            map[token]*providerDef // 根容器 (含全局模块的导出)
+	rootDefs             []*providerDef         // 直接 Provide 到根容器的定义，Init 时构建
+	container            []interface{}          // 按拓扑序构建完成的实例，用于扫描生命周期钩子
+	tracked              map[interface{}]bool
+	errs                 []error // 注册阶段的错误，Init 时统一返回
+	mu                   sync.Mutex
+	globalGuards         []CanActivate
+	globalInterceptors   []NestInterceptor
+	globalPipes          []PipeTransform
+	globalFilters        []ExceptionFilter
+	customDecorators     map[reflect.Type]func(c *gin.Context) interface{} // 补回：自定义参数装饰器
+	validate             *validator.Validate                               // 增加：内置校验器
+	bindingValidate      *validator.Validate                               // binding tag 的校验器，与 gin 全局的引擎相互独立
+	translators          map[*validator.Validate]*ut.UniversalTranslator   // 各校验器对应的 zh / en 翻译器
+	validationMessages   map[string]string                                 // 各语言的校验失败提示
+	modules              []*moduleRef                                      // 按导入顺序编译后的模块
+	routes               []RouteInfo                                       // 路由注册表，用于生成 OpenAPI 文档
+	gateways             []gatewayMount                                    // UseGateway 声明的网关，Init 时挂载
+	gatewayServers       []*GatewayServer                                  // 已挂载的网关，关闭时退订广播适配器
+	overrides            map[token]interface{}                             // 测试替身，仅 TestingApp 使用
+	overridden           map[token]bool                                    // 已命中的替身
+	guardOverrides       map[reflect.Type]CanActivate
+	interceptorOverrides map[reflect.Type]NestInterceptor
+	lifecycle            *LifecycleOptions // Bootstrap 时从容器读取
+	lifecycleLogger      LifecycleLogger
+	booted               []interface{} // 已执行 OnModuleInit 的实例，关闭时按逆序执行钩子
+	microservices        []*microservice
+	messageEngine        *gin.Engine     // MessagePattern 处理器的内部路由表
+	messagePatterns      map[string]bool // 已声明的消息模式
+	serializers          []Serializer    // 内容协商可选的序列化器，按优先级排列
+	formatQuery          string          // 覆盖 Accept 的查询参数名，EnableFormatQuery 启用前为空
+	versioning           *versioning     // EnableVersioning 启用后非 nil
+	initialized          bool            // Init 成功完成
+	initErr              error           // Init 失败的原因：路由可能已部分挂载，重试时直接返回
 }
 
 func New() *GnestApp {
-	return &GnestApp{
-		Engine:           gin.Default(),
-		providers:        make(map[reflect.Type]reflect.Value),
-		customDecorators: make(map[reflect.Type]func(c *gin.Context) interface{}),
-		validate:         validator.New(),
-		globalFilters:    []ExceptionFilter{&DefaultExceptionFilter{}},
+	app := &GnestApp{
+		Engine:             gin.Default(),
+		providers:          make(map[token]*providerDef),
+		tracked:            make(map[interface{}]bool),
+		customDecorators:   make(map[reflect.Type]func(c *gin.Context) interface{}),
+		validate:           validator.New(),
+		bindingValidate:    newBindingValidator(),
+		translators:        make(map[*validator.Validate]*ut.UniversalTranslator),
+		validationMessages: defaultValidationMessages(),
+		serializers:        defaultSerializers(),
 	}
+	app.setupValidation()
+	app.Provide(&Reflector{})                   // 内置 Provider，供守卫 / 拦截器读取路由元数据
+	app.Provide(NewEventBus(EventBusOptions{})) // 内置事件总线
+	app.Provide(NewCommandBus(), NewQueryBus()) // 内置命令 / 查询总线
+	return app
 }
 
-// Provide 注册并注入依赖
+// Provide 注册依赖到根容器：实例、Factory(...)、As[I](...)、Named(...) 均可，
+// 实例在 Init 时按依赖顺序构建并注入
 func (app *GnestApp) Provide(ps ...interface{}) *GnestApp {
 	for _, p := range ps {
-		v := reflect.ValueOf(p)
-		app.providers[v.Type()] = v
-		app.container = append(app.container, p)
-	}
-	// 递归注入并检测循环依赖
-	for _, p := range app.container {
-		app.inject(reflect.ValueOf(p), make(map[reflect.Type]bool))
-	}
-	return app
-}
-
-func (app *GnestApp) inject(v reflect.Value, path map[reflect.Type]bool) {
-	// 只有指针能被 Elem()，且只有指针指向的结构体字段才能被 Set
-	if v.Kind() != reflect.Ptr {
-		return
-	}
-
-	el := v.Elem()
-	if el.Kind() != reflect.Struct {
-		return
-	}
-
-	t := el.Type()
-	if path[t] {
-		log.Fatalf("[Gnest Error] Circular dependency detected: %v", t)
-	}
-	path[t] = true
-	defer delete(path, t)
-
-	for i := 0; i < el.NumField(); i++ {
-		f := el.Field(i)
-		if f.CanSet() {
-			if p, ok := app.providers[f.Type()]; ok {
-				f.Set(p)
-				// 递归注入
-				app.inject(p, path)
-			}
+		def, err := newProviderDef(p, nil)
+		if err == nil {
+			err = app.applyOverride(def)
+		}
+		if err != nil {
+			app.errs = append(app.errs, err)
+			continue
 		}
+		app.providers[def.tok] = def
+		app.rootDefs = append(app.rootDefs, def)
 	}
+	return app
 }
 
 // AddCustomDecorator 注册自定义参数装饰器
@@ -193,6 +227,11 @@ type RouterGroup struct {
 	interceptors []NestInterceptor
 	pipes        []PipeTransform
 	filters      []ExceptionFilter
+	module       *moduleRef             // 控制器所属模块，参数注入按该模块作用域解析
+	controller   *providerDef           // 声明路由的控制器，请求作用域时每个请求重新构建
+	tags         []string               // OpenAPI 标签
+	metadata     map[string]interface{} // 组级元数据
+	messages     bool                   // 消息处理器 (MessagePattern)，不登记到 OpenAPI
 }
 
 func (app *GnestApp) Group(path string) *RouterGroup {
@@ -216,14 +255,16 @@ func (rg *RouterGroup) UseFilters(fs ...ExceptionFilter) *RouterGroup {
 	return rg
 }
 
-func (rg *RouterGroup) processError(c *gin.Context, err error, fs []ExceptionFilter) {
-	// 严格按顺序执行：方法级 -> 组级 -> 全局(含内置兜底)
-	for _, f := range fs {
-		f.Catch(c, err)
-		if c.IsAborted() {
-			return
-		}
+// apiTags 未显式声明时，以路由组前缀的第一段作为标签
+func (rg *RouterGroup) apiTags() []string {
+	if len(rg.tags) > 0 {
+		return rg.tags
 	}
+	seg := strings.SplitN(strings.Trim(rg.ginGroup.BasePath(), "/"), "/", 2)[0]
+	if seg == "" {
+		return nil
+	}
+	return []string{seg}
 }
 
 // ==========================================
@@ -234,13 +275,37 @@ func (rg *RouterGroup) Handle(method, path string, handler interface{}, methodEn
 	hVal := reflect.ValueOf(handler)
 	hTyp := hVal.Type()
 
+	// 控制器的方法表达式 (*Ctrl).Method：接收者由容器提供，请求作用域的控制器每个请求新建实例。
+	// 请求作用域的控制器只能以方法表达式注册，方法值已绑定到注册时的原型上
+	var ctrlRecv bool
+	if c := rg.controller; c != nil {
+		ctrlRecv = hTyp.NumIn() > 0 && hTyp.In(0) == c.value.Type()
+		if ctrlRecv {
+			hTyp = withoutReceiver(hTyp)
+		} else if scope := rg.app.lifetime(c); scope != Singleton {
+			rg.app.errs = append(rg.app.errs, fmt.Errorf("%s %s: %v is %v-scoped, register its handlers as method expressions such as (%v).Method",
+				method, joinPaths(rg.ginGroup.BasePath(), path), c.tok, scope, c.tok))
+			return
+		}
+	}
+
 	// 1. 编译期提取增强器
 	var mGuards []CanActivate
 	var mInterceptors []NestInterceptor
 	var mPipes []PipeTransform
 	var mFilters []ExceptionFilter
+	var operation *Operation
+	var markers []ParamInfo
+	var metadata []Metadata
+	var unsupported []error
 	for _, e := range methodEnhancers {
 		switch v := e.(type) {
+		case Metadata:
+			metadata = append(metadata, v)
+		case *Operation:
+			operation = v
+		case paramMarker:
+			markers = append(markers, v.paramInfo())
 		case CanActivate:
 			mGuards = append(mGuards, v)
 		case NestInterceptor:
@@ -249,23 +314,68 @@ func (rg *RouterGroup) Handle(method, path string, handler interface{}, methodEn
 			mPipes = append(mPipes, v)
 		case ExceptionFilter:
 			mFilters = append(mFilters, v)
+		case gin.HandlerFunc, func(*gin.Context):
+			// gin 中间件不在 gnest 的管道内执行，改写为守卫或通过 app.Use 注册
+			unsupported = append(unsupported, fmt.Errorf("gin middleware %T is not a route enhancer, implement it as a guard (CanActivate)", v))
+		default:
+			unsupported = append(unsupported, fmt.Errorf("unsupported route enhancer %T", v))
 		}
 	}
+	if len(unsupported) > 0 {
+		rg.app.errs = append(rg.app.errs, fmt.Errorf("%s %s: %w", method, joinPaths(rg.ginGroup.BasePath(), path), errors.Join(unsupported...)))
+		return
+	}
+
+	params, err := bindParamMarkers(hTyp, markers)
+	if err != nil {
+		rg.app.errs = append(rg.app.errs, fmt.Errorf("%s %s: %w", method, joinPaths(rg.ginGroup.BasePath(), path), err))
+		return
+	}
+	md := newRouteMetadata(rg.metadata, metadata)
+
+	// 路由表中的一项，供 OpenAPI 文档使用
+	info := RouteInfo{
+		Method:    method,
+		Path:      joinPaths(rg.ginGroup.BasePath(), path),
+		Handler:   hTyp,
+		Params:    params,
+		Operation: operation,
+		Tags:      rg.apiTags(),
+	}
 
 	// 2. 预合并链条
-	fGuards := concat(rg.app.globalGuards, rg.guards, mGuards)
-	fInterceptors := concat(rg.app.globalInterceptors, rg.interceptors, mInterceptors)
+	fGuards := rg.app.overrideGuards(concat(rg.app.globalGuards, rg.guards, mGuards))
+	fInterceptors := rg.app.overrideInterceptors(concat(rg.app.globalInterceptors, rg.interceptors, mInterceptors))
 	fPipes := concat(rg.app.globalPipes, rg.pipes, mPipes)
-	fFilters := concat(mFilters, rg.filters, rg.app.globalFilters)
+	// 内置兜底过滤器始终排在最后，保证用户注册的全局过滤器有机会执行
+	fFilters := newFilterChain(concat(mFilters, rg.filters, rg.app.globalFilters, []ExceptionFilter{defaultFilter}))
+	var preparers []requestPreparer
+	for _, i := range fInterceptors {
+		if p, ok := i.(requestPreparer); ok {
+			preparers = append(preparers, p)
+		}
+	}
 
 	// 3. 预设参数工厂
 	factories := make([]argumentResolver, hTyp.NumIn())
 	for i := 0; i < hTyp.NumIn(); i++ {
-		factories[i] = rg.app.makeParamFactory(hTyp.In(i))
+		if params[i] != nil {
+			factories[i] = makeMarkerResolver(*params[i])
+			continue
+		}
+		factories[i] = rg.app.makeParamFactory(hTyp.In(i), rg.module)
 	}
 
 	// 4. 运行时 Handler
 	coreHandler := func(c *gin.Context) {
+		rs := rg.app.beginRequest(c)
+		defer rs.destroy()
+		c.Set(metadataCtxKey, md)
+		prepareContext(c)
+		for _, p := range preparers {
+			p.prepareRequest(c)
+		}
+
 		// A. Panic 捕获与过滤器整合
 		defer func() {
 			if r := recover(); r != nil {
@@ -281,7 +391,7 @@ func (rg *RouterGroup) Handle(method, path string, handler interface{}, methodEn
 		for _, g := range fGuards {
 			if !g.CanActivate(c) {
 				if !c.IsAborted() {
-					c.AbortWithStatus(http.StatusForbidden)
+					rg.processError(c, guardError(c), fFilters)
 				}
 				return
 			}
@@ -310,7 +420,18 @@ func (rg *RouterGroup) Handle(method, path string, handler interface{}, methodEn
 			}
 
 			// D. 执行真正的业务方法
+			if ctrlRecv {
+				ctrl, err := rg.app.resolve(rg.controller, rs, nil)
+				if err != nil {
+					return err
+				}
+				args = append([]reflect.Value{ctrl}, args...)
+			}
 			res := hVal.Call(args)
+			// 形如 (T, error) 的处理函数：错误非空时交给过滤器
+			if n := len(res); n > 1 && hTyp.Out(n-1) == errorType && !res[n-1].IsNil() {
+				return res[n-1].Interface()
+			}
 			if len(res) > 0 {
 				return res[0].Interface()
 			}
@@ -324,11 +445,35 @@ func (rg *RouterGroup) Handle(method, path string, handler interface{}, methodEn
 			return
 		}
 		rg.processResponse(c, result, fFilters)
+		runAfterResponse(c)
 	}
 
+	if rg.messages {
+		rg.ginGroup.Handle(method, path, coreHandler)
+		return
+	}
+	// 启用接口版本时按版本挂载
+	if rg.app.versioning != nil {
+		rg.app.addVersioned(versionedRoute{method: method, path: info.Path, versions: routeVersions(md), handler: coreHandler, info: info})
+		return
+	}
+	rg.app.routes = append(rg.app.routes, info)
 	rg.ginGroup.Handle(method, path, coreHandler)
 }
 
+// withoutReceiver 去掉方法表达式的接收者参数，得到参与参数绑定与文档生成的签名
+func withoutReceiver(t reflect.Type) reflect.Type {
+	in := make([]reflect.Type, t.NumIn()-1)
+	for i := range in {
+		in[i] = t.In(i + 1)
+	}
+	out := make([]reflect.Type, t.NumOut())
+	for i := range out {
+		out[i] = t.Out(i)
+	}
+	return reflect.FuncOf(in, out, t.IsVariadic())
+}
+
 func (rg *RouterGroup) GET(path string, h interface{}, m ...interface{}) {
 	rg.Handle("GET", path, h, m...)
 }
@@ -436,16 +581,21 @@ func (app *GnestApp) TRACE(path string, handler interface{}, m ...interface{}) {
 // 5. 参数绑定与底层支持 (Underlying Support)
 // ==========================================
 
-func (app *GnestApp) makeParamFactory(t reflect.Type) argumentResolver {
+func (app *GnestApp) makeParamFactory(t reflect.Type, scope *moduleRef) argumentResolver {
 	// --- 1. 基础类型处理 (Context, Req, Res) ---
 	switch t.String() {
 	case "*gin.Context":
 		return func(c *gin.Context) (reflect.Value, error) { return reflect.ValueOf(c), nil }
 	case "*http.Request":
 		return func(c *gin.Context) (reflect.Value, error) { return reflect.ValueOf(c.Request), nil }
+	case "context.Context":
+		// 请求的 ctx：携带请求 ID / 当前用户，客户端断开或超时时取消
+		return func(c *gin.Context) (reflect.Value, error) { return reflect.ValueOf(c.Request.Context()), nil }
+	case "*gnest.Message":
+		return func(c *gin.Context) (reflect.Value, error) { return reflect.ValueOf(MessageOf(c)), nil }
 	}
 
-	// --- 2. 文件处理 (@UploadedFile) ---
+	// --- 2. 文件处理 (@UploadedFile)：未使用 UploadedFile 标记时按默认字段名 file / files 读取 ---
 	if t.String() == "*multipart.FileHeader" {
 		return func(c *gin.Context) (reflect.Value, error) {
 			f, err := c.FormFile("file") // 这里可进一步优化为根据参数名取
@@ -470,8 +620,8 @@ func (app *GnestApp) makeParamFactory(t reflect.Type) argumentResolver {
 	}
 
 	// --- 4. 依赖注入处理 (Provider) ---
-	if p, ok := app.providers[t]; ok {
-		return func(c *gin.Context) (reflect.Value, error) { return p, nil }
+	if def, ok := app.lookup(scope, token{typ: t}); ok {
+		return func(c *gin.Context) (reflect.Value, error) { return app.resolve(def, requestScopeOf(c), nil) }
 	}
 
 	// --- 5. 核心：DTO 结构体智能绑定 (Body/Query/Param/Header) ---
@@ -500,7 +650,11 @@ func (app *GnestApp) createStructResolver(st reflect.Type) argumentResolver {
 	}
 
 	return func(c *gin.Context) (reflect.Value, error) {
+		if mc := messageContextOf(c); mc != nil {
+			return app.decodeMessage(c, mc, st)
+		}
 		obj := reflect.New(st).Interface()
+		defer skipGinValidation(obj)()
 
 		// 只有存在相关 Tag 时才调用对应的绑定器，减少性能损耗
 		if hasUriTag {
@@ -512,17 +666,53 @@ func (app *GnestApp) createStructResolver(st reflect.Type) argumentResolver {
 
 		// 默认绑定 Body 或 Query
 		if err := c.ShouldBind(obj); err != nil {
-			return reflect.Value{}, err
+			// 请求体无法解析 (JSON 格式错误、类型不匹配等) 属于客户端错误
+			return reflect.Value{}, BadRequest("malformed request body").WithCause(err)
 		}
 
-		// 自动校验
-		if err := app.validate.Struct(obj); err != nil {
-			return reflect.Value{}, err
+		// 自动校验：binding 与 validate 两种 tag 均由本应用的校验器处理
+		for _, v := range app.validators() {
+			if err := v.Struct(obj); err != nil {
+				return reflect.Value{}, app.validationException(c, v, st, err)
+			}
 		}
 		return reflect.ValueOf(obj), nil
 	}
 }
 
+const afterResponseKey = "gnest.afterResponse"
+
+// afterResponse 登记在成功响应写出后执行的回调，如 CacheInterceptor 保存最终的响应体
+func afterResponse(c *gin.Context, fn func()) {
+	fns, _ := c.Get(afterResponseKey)
+	hooks, _ := fns.([]func())
+	c.Set(afterResponseKey, append(hooks, fn))
+}
+
+func runAfterResponse(c *gin.Context) {
+	fns, _ := c.Get(afterResponseKey)
+	hooks, _ := fns.([]func())
+	for _, fn := range hooks {
+		fn()
+	}
+}
+
+// bufferedResponse 内部请求的 http.ResponseWriter，不依赖 httptest，
+// 只在内存中记录状态码、响应头与响应体
+type bufferedResponse struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func newBufferedResponse() *bufferedResponse {
+	return &bufferedResponse{header: make(http.Header), status: http.StatusOK}
+}
+
+func (w *bufferedResponse) Header() http.Header         { return w.header }
+func (w *bufferedResponse) WriteHeader(status int)      { w.status = status }
+func (w *bufferedResponse) Write(b []byte) (int, error) { return w.body.Write(b) }
+
 func (app *GnestApp) execInterceptors(c *gin.Context, is []NestInterceptor, i int, next func() interface{}) interface{} {
 	if i >= len(is) {
 		return next()
@@ -530,7 +720,7 @@ func (app *GnestApp) execInterceptors(c *gin.Context, is []NestInterceptor, i in
 	return is[i].Intercept(c, func() interface{} { return app.execInterceptors(c, is, i+1, next) })
 }
 
-func (rg *RouterGroup) processResponse(c *gin.Context, res interface{}, fs []ExceptionFilter) {
+func (rg *RouterGroup) processResponse(c *gin.Context, res interface{}, fs *filterChain) {
 	if c.IsAborted() || c.Writer.Written() {
 		return
 	}
@@ -558,6 +748,14 @@ func (rg *RouterGroup) processResponse(c *gin.Context, res interface{}, fs []Exc
 		} else {
 			c.File(v.FilePath)
 		}
+	case SSEResult:
+		writeSSE(c, v)
+	case StreamResult:
+		writeStream(c, v)
+	case ChunkedResult:
+		if err := writeChunked(c, v); err != nil {
+			rg.processError(c, err, fs)
+		}
 	case string: // 返回纯字符串
 		c.String(http.StatusOK, v)
 	case []byte: // 返回原始字节
@@ -565,15 +763,18 @@ func (rg *RouterGroup) processResponse(c *gin.Context, res interface{}, fs []Exc
 	case nil:
 		return
 	default:
-		// 默认返回 JSON
-		c.JSON(http.StatusOK, res)
+		// 按 Accept (及 EnableFormatQuery 的参数) 协商输出格式，默认 JSON
+		if err := rg.app.negotiate(c, res); err != nil {
+			rg.processError(c, err, fs)
+		}
 	}
 }
 
 // 生命周期逻辑
 func (app *GnestApp) ListenAndServe(addr string) {
-	app.callHook("OnModuleInit")
-	app.callHook("OnApplicationBootstrap")
+	if err := app.Bootstrap(context.Background()); err != nil {
+		log.Fatalf("[Gnest Error] %v", err)
+	}
 	srv := &http.Server{Addr: addr, Handler: app.Engine}
 	go func() {
 		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
@@ -584,27 +785,11 @@ func (app *GnestApp) ListenAndServe(addr string) {
 	stop := make(chan os.Signal, 1)
 	signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
 	sig := <-stop
-	// 3. 销毁序列
-	app.callHook("OnModuleDestroy") // 补齐这个！
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-	app.callHook("BeforeApplicationShutdown", sig.String())
-	srv.Shutdown(ctx)
-	app.callHook("OnApplicationShutdown")
-	log.Println("[Gnest] Shutdown finished")
-}
-
-func (app *GnestApp) callHook(name string, args ...interface{}) {
-	for _, inst := range app.container {
-		v := reflect.ValueOf(inst)
-		if m := v.MethodByName(name); m.IsValid() {
-			in := make([]reflect.Value, len(args))
-			for i, a := range args {
-				in[i] = reflect.ValueOf(a)
-			}
-			m.Call(in)
-		}
+	// 销毁序列：OnModuleDestroy -> BeforeApplicationShutdown -> 关闭 HTTP 服务 -> OnApplicationShutdown
+	if err := app.shutdown(context.Background(), sig.String(), srv); err != nil {
+		log.Printf("[Gnest] Shutdown error: %v", err)
 	}
+	log.Println("[Gnest] Shutdown finished")
 }
 
 func concat[T any](ss ...[]T) []T {
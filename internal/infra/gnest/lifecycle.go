@@ -0,0 +1,244 @@
+package gnest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// ==========================================
+// 生命周期钩子 (Lifecycle Hooks)
+// ==========================================
+
+const (
+	defaultHookTimeout     = 10 * time.Second
+	defaultShutdownTimeout = 5 * time.Second
+)
+
+// LifecycleOptions 生命周期钩子的超时配置，以 *LifecycleOptions Provider 注册到根容器 (或由全局模块导出) 后生效：
+//
+//	gnest.Factory(func(cfg *config.Config) *gnest.LifecycleOptions { ... })
+type LifecycleOptions struct {
+	HookTimeout     time.Duration            // 单个钩子的默认超时，默认 10s
+	ShutdownTimeout time.Duration            // 等待 HTTP 请求处理完毕的超时，默认 5s
+	Hooks           map[string]time.Duration // 按钩子名覆盖 (不区分大小写)，如 OnModuleInit: 30s
+}
+
+// timeout 返回指定钩子的超时
+func (o *LifecycleOptions) timeout(hook string) time.Duration {
+	for name, d := range o.Hooks {
+		if strings.EqualFold(name, hook) && d > 0 {
+			return d
+		}
+	}
+	if o.HookTimeout > 0 {
+		return o.HookTimeout
+	}
+	return defaultHookTimeout
+}
+
+func (o *LifecycleOptions) shutdownTimeout() time.Duration {
+	if o.ShutdownTimeout > 0 {
+		return o.ShutdownTimeout
+	}
+	return defaultShutdownTimeout
+}
+
+// LifecycleLogger 记录钩子的执行结果；容器中实现该接口的实例 (如 LoggerService) 会被自动使用，
+// 未提供时退回标准库 log
+type LifecycleLogger interface {
+	LogLifecycleHook(hook, provider string, timeout, elapsed time.Duration, err error)
+}
+
+type stdLifecycleLogger struct{}
+
+func (stdLifecycleLogger) LogLifecycleHook(hook, provider string, timeout, elapsed time.Duration, err error) {
+	if err != nil {
+		log.Printf("[Gnest] %s %s failed after %v (timeout %v): %v", provider, hook, elapsed, timeout, err)
+		return
+	}
+	log.Printf("[Gnest] %s %s done in %v", provider, hook, elapsed)
+}
+
+// lifecycleHook 描述一个钩子：bind 在实例实现了该钩子时返回待执行的函数，否则返回 nil
+type lifecycleHook struct {
+	name string
+	bind func(inst interface{}, sig string) func(ctx context.Context) error
+}
+
+var (
+	hookModuleInit = lifecycleHook{"OnModuleInit", func(inst interface{}, _ string) func(context.Context) error {
+		if h, ok := inst.(OnModuleInit); ok {
+			return h.OnModuleInit
+		}
+		return nil
+	}}
+	hookApplicationBootstrap = lifecycleHook{"OnApplicationBootstrap", func(inst interface{}, _ string) func(context.Context) error {
+		if h, ok := inst.(OnApplicationBootstrap); ok {
+			return h.OnApplicationBootstrap
+		}
+		return nil
+	}}
+	hookModuleDestroy = lifecycleHook{"OnModuleDestroy", func(inst interface{}, _ string) func(context.Context) error {
+		if h, ok := inst.(OnModuleDestroy); ok {
+			return h.OnModuleDestroy
+		}
+		return nil
+	}}
+	hookBeforeApplicationShutdown = lifecycleHook{"BeforeApplicationShutdown", func(inst interface{}, sig string) func(context.Context) error {
+		if h, ok := inst.(BeforeApplicationShutdown); ok {
+			return func(ctx context.Context) error { return h.BeforeApplicationShutdown(ctx, sig) }
+		}
+		return nil
+	}}
+	hookApplicationShutdown = lifecycleHook{"OnApplicationShutdown", func(inst interface{}, sig string) func(context.Context) error {
+		if h, ok := inst.(OnApplicationShutdown); ok {
+			return func(ctx context.Context) error { return h.OnApplicationShutdown(ctx, sig) }
+		}
+		return nil
+	}}
+)
+
+var lifecycleOptionsType = reflect.TypeOf((*LifecycleOptions)(nil))
+
+// Bootstrap 挂载路由后按依赖顺序执行 OnModuleInit 与 OnApplicationBootstrap，最后开始监听微服务消息；
+// 任一步骤失败即中止启动，并对已初始化的实例按逆序执行关闭钩子
+func (app *GnestApp) Bootstrap(ctx context.Context) error {
+	if err := app.Init(); err != nil {
+		return err
+	}
+	if err := app.loadLifecycle(); err != nil {
+		return err
+	}
+	n, err := app.runHook(ctx, hookModuleInit, app.container, "", true)
+	app.booted = app.container[:n]
+	if err == nil {
+		_, err = app.runHook(ctx, hookApplicationBootstrap, app.booted, "", true)
+	}
+	if err == nil {
+		err = app.startMicroservices()
+	}
+	if err != nil {
+		if serr := app.Shutdown(ctx, "bootstrap failed"); serr != nil {
+			err = errors.Join(err, serr)
+		}
+		return err
+	}
+	return nil
+}
+
+// Shutdown 先停止接收微服务消息，再按依赖逆序执行 OnModuleDestroy、BeforeApplicationShutdown 与 OnApplicationShutdown，
+// 单个钩子失败不影响其余钩子，错误合并返回
+func (app *GnestApp) Shutdown(ctx context.Context, sig string) error {
+	return app.shutdown(ctx, sig, nil)
+}
+
+// shutdown 在 BeforeApplicationShutdown 之后关闭 HTTP 服务 (srv 可为 nil)
+func (app *GnestApp) shutdown(ctx context.Context, sig string, srv *http.Server) error {
+	insts := make([]interface{}, len(app.booted))
+	for i, inst := range app.booted {
+		insts[len(insts)-1-i] = inst
+	}
+	app.booted = nil
+
+	var errs []error
+	if err := app.closeMicroservices(); err != nil {
+		errs = append(errs, err)
+	}
+	app.closeGateways()
+	for _, h := range []lifecycleHook{hookModuleDestroy, hookBeforeApplicationShutdown} {
+		if _, err := app.runHook(ctx, h, insts, sig, false); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if srv != nil {
+		sctx, cancel := context.WithTimeout(ctx, app.lifecycle.shutdownTimeout())
+		if err := srv.Shutdown(sctx); err != nil {
+			errs = append(errs, fmt.Errorf("http server shutdown: %w", err))
+		}
+		cancel()
+	}
+	if _, err := app.runHook(ctx, hookApplicationShutdown, insts, sig, false); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+// loadLifecycle 读取容器中的超时配置与日志记录器
+func (app *GnestApp) loadLifecycle() error {
+	app.lifecycle = &LifecycleOptions{}
+	if def, ok := app.providers[token{typ: lifecycleOptionsType}]; ok {
+		v, err := app.resolve(def, nil, nil)
+		if err != nil {
+			return err
+		}
+		if opts, _ := v.Interface().(*LifecycleOptions); opts != nil {
+			app.lifecycle = opts
+		}
+	}
+	app.lifecycleLogger = stdLifecycleLogger{}
+	for _, inst := range app.container {
+		if l, ok := inst.(LifecycleLogger); ok {
+			app.lifecycleLogger = l
+			break
+		}
+	}
+	return nil
+}
+
+// runHook 依次对实例执行钩子，返回已成功执行的实例数；
+// abort 为 true 时遇到错误立即返回，否则执行完全部实例后合并错误
+func (app *GnestApp) runHook(ctx context.Context, h lifecycleHook, insts []interface{}, sig string, abort bool) (int, error) {
+	if app.lifecycle == nil {
+		if err := app.loadLifecycle(); err != nil {
+			return 0, err
+		}
+	}
+	timeout := app.lifecycle.timeout(h.name)
+	var errs []error
+	for i, inst := range insts {
+		fn := h.bind(inst, sig)
+		if fn == nil {
+			continue
+		}
+		start := time.Now()
+		err := callWithTimeout(ctx, timeout, fn)
+		provider := fmt.Sprintf("%T", inst)
+		app.lifecycleLogger.LogLifecycleHook(h.name, provider, timeout, time.Since(start), err)
+		if err != nil {
+			err = fmt.Errorf("%s %s: %w", provider, h.name, err)
+			if abort {
+				return i, err
+			}
+			errs = append(errs, err)
+		}
+	}
+	return len(insts), errors.Join(errs...)
+}
+
+// callWithTimeout 在独立 goroutine 中执行钩子，超时后不再等待；钩子应监听 ctx 及时退出
+func callWithTimeout(ctx context.Context, timeout time.Duration, fn func(ctx context.Context) error) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- fmt.Errorf("panic: %v", r)
+			}
+		}()
+		done <- fn(ctx)
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return fmt.Errorf("timed out after %v: %w", timeout, ctx.Err())
+	}
+}
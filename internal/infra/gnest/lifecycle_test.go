@@ -0,0 +1,19 @@
+package gnest_test
+
+import (
+	"testing"
+
+	"blog/internal/infra/gnest"
+)
+
+func TestInitFailureIsSticky(t *testing.T) {
+	app := gnest.New()
+	app.GET("/bad/:id", func(id string) string { return id }, gnest.Path[int]{"id"})
+	first := app.Init()
+	if first == nil {
+		t.Fatal("expected Init to fail")
+	}
+	if second := app.Init(); second == nil || second.Error() != first.Error() {
+		t.Errorf("expected the retry to return the same error, got %v", second)
+	}
+}
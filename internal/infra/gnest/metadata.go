@@ -0,0 +1,143 @@
+package gnest
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+// ==========================================
+// 路由元数据 (Metadata & Reflector)
+// ==========================================
+
+// 内置元数据键
+const (
+	IsPublicKey    = "isPublic"
+	RolesKey       = "roles"
+	PermissionsKey = "permissions"
+)
+
+const metadataCtxKey = "gnest.metadata"
+
+// Metadata 作为方法级增强器传入即为路由元数据，对应 Nest 的 @SetMetadata
+type Metadata struct {
+	Key   string
+	Value interface{}
+}
+
+// SetMetadata 为路由附加元数据，守卫 / 拦截器通过 Reflector 读取
+func SetMetadata(key string, value interface{}) Metadata {
+	return Metadata{Key: key, Value: value}
+}
+
+// Public 标记无需鉴权的路由
+func Public() Metadata {
+	return SetMetadata(IsPublicKey, true)
+}
+
+// Roles 声明访问路由所需的角色，如 gnest.Roles(constants.Admin)
+func Roles[R any](roles ...R) Metadata {
+	return SetMetadata(RolesKey, roles)
+}
+
+// Permissions 声明访问路由所需的权限点
+func Permissions(permissions ...string) Metadata {
+	return SetMetadata(PermissionsKey, permissions)
+}
+
+// SetMetadata 为路由组下的所有路由附加元数据，路由上的同名元数据优先
+func (rg *RouterGroup) SetMetadata(key string, value interface{}) *RouterGroup {
+	return rg.UseMetadata(SetMetadata(key, value))
+}
+
+// UseMetadata 批量附加组级元数据，如 rg.UseMetadata(gnest.Roles(constants.Admin))；
+// 只作用于之后注册的路由，需在声明路由之前调用
+func (rg *RouterGroup) UseMetadata(ms ...Metadata) *RouterGroup {
+	if rg.metadata == nil {
+		rg.metadata = make(map[string]interface{})
+	}
+	for _, m := range ms {
+		rg.metadata[m.Key] = m.Value
+	}
+	return rg
+}
+
+// routeMetadata 是一条路由在注册阶段合并好的元数据
+type routeMetadata struct {
+	route map[string]interface{}
+	group map[string]interface{}
+}
+
+// newRouteMetadata 复制注册时的组级元数据，之后对路由组的 UseMetadata 只影响后续注册的路由
+func newRouteMetadata(group map[string]interface{}, ms []Metadata) *routeMetadata {
+	md := &routeMetadata{route: make(map[string]interface{}, len(ms)), group: make(map[string]interface{}, len(group))}
+	for k, v := range group {
+		md.group[k] = v
+	}
+	for _, m := range ms {
+		md.route[m.Key] = m.Value
+	}
+	return md
+}
+
+// Reflector 读取当前请求所匹配路由的元数据，作为 Provider 注册在根容器中，可直接注入：
+//
+//	type RolesGuard struct {
+//		Reflector *gnest.Reflector
+//	}
+type Reflector struct{}
+
+// Get 读取元数据，路由级覆盖组级
+func (r *Reflector) Get(c *gin.Context, key string) (interface{}, bool) {
+	md := metadataOf(c)
+	if md == nil {
+		return nil, false
+	}
+	if v, ok := md.route[key]; ok {
+		return v, true
+	}
+	v, ok := md.group[key]
+	return v, ok
+}
+
+// GetAll 按 路由级 -> 组级 的顺序返回所有已声明的值
+func (r *Reflector) GetAll(c *gin.Context, key string) []interface{} {
+	md := metadataOf(c)
+	if md == nil {
+		return nil
+	}
+	var vs []interface{}
+	if v, ok := md.route[key]; ok {
+		vs = append(vs, v)
+	}
+	if v, ok := md.group[key]; ok {
+		vs = append(vs, v)
+	}
+	return vs
+}
+
+// IsPublic 当前路由是否被 Public() 标记
+func (r *Reflector) IsPublic(c *gin.Context) bool {
+	v, _ := GetMetadata[bool](c, IsPublicKey)
+	return v
+}
+
+// GetMetadata 读取指定类型的元数据，类型不匹配时返回零值：
+//
+//	roles, _ := gnest.GetMetadata[[]constants.Role](c, gnest.RolesKey)
+func GetMetadata[T any](c *gin.Context, key string) (T, bool) {
+	var zero T
+	v, ok := (*Reflector)(nil).Get(c, key)
+	if !ok {
+		return zero, false
+	}
+	t, ok := v.(T)
+	return t, ok
+}
+
+func metadataOf(c *gin.Context) *routeMetadata {
+	v, ok := c.Get(metadataCtxKey)
+	if !ok {
+		return nil
+	}
+	md, _ := v.(*routeMetadata)
+	return md
+}
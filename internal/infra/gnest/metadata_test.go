@@ -0,0 +1,36 @@
+package gnest_test
+
+import (
+	"testing"
+
+	"blog/internal/infra/gnest"
+
+	"github.com/gin-gonic/gin"
+)
+
+type metadataController struct{}
+
+func (metadataController) Prefix() string { return "/md" }
+func (metadataController) Routes(rg *gnest.RouterGroup) {
+	isPublic := func(c *gin.Context) bool {
+		v, _ := gnest.GetMetadata[bool](c, gnest.IsPublicKey)
+		return v
+	}
+	rg.GET("/before", isPublic)
+	rg.UseMetadata(gnest.Public())
+	rg.GET("/after", isPublic)
+}
+
+func TestGroupMetadataAppliesToLaterRoutes(t *testing.T) {
+	app := gnest.NewTestingApp(&gnest.Module{Name: "Metadata", Controllers: []gnest.Controller{metadataController{}}})
+	if err := app.Compile(); err != nil {
+		t.Fatal(err)
+	}
+	client := app.Client(t)
+	if body := client.GET("/md/before").Response().Body.String(); body != "false" {
+		t.Errorf("route declared before UseMetadata: expected false, got %s", body)
+	}
+	if body := client.GET("/md/after").Response().Body.String(); body != "true" {
+		t.Errorf("route declared after UseMetadata: expected true, got %s", body)
+	}
+}
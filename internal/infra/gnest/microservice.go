@@ -0,0 +1,259 @@
+package gnest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ==========================================
+// 微服务传输层 (Microservices)
+// ==========================================
+
+// 控制器在 Routes 中声明消息处理器，与 HTTP 路由共享守卫 / 拦截器 / 管道 / 过滤器：
+//
+//	rg.MessagePattern("post.published", ctrl.OnPostPublished, &guards.InternalGuard{})
+//	func (ctrl *PostController) OnPostPublished(c *gin.Context, dto *PostPublishedDTO) error
+//
+// 结构体 DTO 参数由传输层的 Decoder 从消息体解码并按 validate / binding 标签校验；
+// 处理失败 (含守卫拒绝、校验失败、panic) 的错误交给传输层处理，如投递到死信队列
+
+// messageMethod 消息处理器在内部路由表中使用的方法名
+const messageMethod = "MESSAGE"
+
+// Message 传输层无关的消息
+type Message struct {
+	Pattern string
+	Key     []byte
+	Value   []byte
+	Headers map[string]string
+	Raw     interface{} // 传输层的原始消息，如 *sarama.ConsumerMessage
+}
+
+// MessageHandler 由 gnest 提供给传输层，返回处理器链中的错误
+type MessageHandler func(ctx context.Context, msg *Message) error
+
+// Transport 微服务传输层：Listen 订阅消息模式并开始投递 (非阻塞)，Close 停止接收
+type Transport interface {
+	Listen(patterns []string, handle MessageHandler) error
+	Close() error
+}
+
+// MicroserviceOptions 微服务配置
+type MicroserviceOptions struct {
+	Decoder func(data []byte, v interface{}) error // DTO 解码，默认 encoding/json，如 kafka.DecodeJSON
+}
+
+type microservice struct {
+	transport Transport
+	opts      MicroserviceOptions
+	listening bool
+}
+
+// messageContext 随消息请求传递，记录解码器与处理器链中的首个错误
+type messageContext struct {
+	msg     *Message
+	decoder func(data []byte, v interface{}) error
+	err     error
+}
+
+type messageCtxKey struct{}
+
+var messageType = reflect.TypeOf((*Message)(nil))
+
+// ErrNoMessageHandler 没有处理器声明该消息模式
+var ErrNoMessageHandler = errors.New("no message handler")
+
+// ConnectMicroservice 连接传输层，Bootstrap 完成后开始监听所有 MessagePattern，关闭时最先停止
+func (app *GnestApp) ConnectMicroservice(t Transport, opts ...MicroserviceOptions) *GnestApp {
+	ms := &microservice{transport: t}
+	if len(opts) > 0 {
+		ms.opts = opts[0]
+	}
+	if ms.opts.Decoder == nil {
+		ms.opts.Decoder = json.Unmarshal
+	}
+	app.microservices = append(app.microservices, ms)
+	return app
+}
+
+// MessagePattern 声明消息处理器，pattern 不受控制器路由前缀影响
+func (rg *RouterGroup) MessagePattern(pattern string, handler interface{}, enhancers ...interface{}) {
+	app := rg.app
+	if app.messageEngine == nil {
+		app.messageEngine = gin.New()
+		app.messageEngine.ContextWithFallback = true
+		app.messagePatterns = make(map[string]bool)
+	}
+	if app.messagePatterns[pattern] {
+		app.errs = append(app.errs, fmt.Errorf("message pattern %q is declared twice", pattern))
+		return
+	}
+	app.messagePatterns[pattern] = true
+
+	mrg := *rg
+	mrg.ginGroup = &app.messageEngine.RouterGroup
+	mrg.messages = true
+	mrg.Handle(messageMethod, "/"+pattern, handler, enhancers...)
+}
+
+// startMicroservices 在 Bootstrap 的最后开始监听
+func (app *GnestApp) startMicroservices() error {
+	if len(app.microservices) == 0 {
+		return nil
+	}
+	patterns := make([]string, 0, len(app.messagePatterns))
+	for p := range app.messagePatterns {
+		patterns = append(patterns, p)
+	}
+	sort.Strings(patterns)
+	for _, ms := range app.microservices {
+		ms := ms
+		handle := func(ctx context.Context, msg *Message) error {
+			return app.dispatchMessage(ctx, msg, ms.opts.Decoder)
+		}
+		if err := ms.transport.Listen(patterns, handle); err != nil {
+			return fmt.Errorf("microservice %T: %w", ms.transport, err)
+		}
+		ms.listening = true
+	}
+	return nil
+}
+
+// closeMicroservices 停止接收消息，在 OnModuleDestroy 之前执行
+func (app *GnestApp) closeMicroservices() error {
+	var errs []error
+	for _, ms := range app.microservices {
+		if !ms.listening {
+			continue
+		}
+		ms.listening = false
+		if err := ms.transport.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("microservice %T: %w", ms.transport, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// dispatchMessage 将消息作为内部请求交给处理器链，返回链中的首个错误
+func (app *GnestApp) dispatchMessage(ctx context.Context, msg *Message, decoder func([]byte, interface{}) error) error {
+	if !app.messagePatterns[msg.Pattern] {
+		return fmt.Errorf("%w for pattern %q", ErrNoMessageHandler, msg.Pattern)
+	}
+	mc := &messageContext{msg: msg, decoder: decoder}
+	req, err := http.NewRequestWithContext(context.WithValue(ctx, messageCtxKey{}, mc), messageMethod, "/"+msg.Pattern, bytes.NewReader(msg.Value))
+	if err != nil {
+		return err
+	}
+	for k, v := range msg.Headers {
+		req.Header.Set(k, v)
+	}
+	app.messageEngine.ServeHTTP(newBufferedResponse(), req)
+	return mc.err
+}
+
+// MessageOf 返回当前正在处理的消息，HTTP 请求中为 nil；处理器也可直接声明 *gnest.Message 参数
+func MessageOf(c *gin.Context) *Message {
+	if mc := messageContextOf(c); mc != nil {
+		return mc.msg
+	}
+	return nil
+}
+
+func messageContextOf(c *gin.Context) *messageContext {
+	if c == nil || c.Request == nil {
+		return nil
+	}
+	mc, _ := c.Request.Context().Value(messageCtxKey{}).(*messageContext)
+	return mc
+}
+
+// decodeMessage 用传输层的解码器解析消息体并校验
+func (app *GnestApp) decodeMessage(c *gin.Context, mc *messageContext, st reflect.Type) (reflect.Value, error) {
+	obj := reflect.New(st).Interface()
+	if err := mc.decoder(mc.msg.Value, obj); err != nil {
+		return reflect.Value{}, BadRequest("malformed message").WithCause(err)
+	}
+	for _, v := range app.validators() {
+		if err := v.Struct(obj); err != nil {
+			return reflect.Value{}, app.validationException(c, v, st, err)
+		}
+	}
+	return reflect.ValueOf(obj), nil
+}
+
+// ==========================================
+// 内存传输层 (In-Memory Transport)
+// ==========================================
+
+// MemoryTransport 进程内传输层，用于在没有消息中间件时测试消息处理器
+type MemoryTransport struct {
+	mu          sync.Mutex
+	handle      MessageHandler
+	patterns    map[string]bool
+	deadLetters []*Message
+}
+
+// NewMemoryTransport 创建内存传输层
+func NewMemoryTransport() *MemoryTransport {
+	return &MemoryTransport{}
+}
+
+func (t *MemoryTransport) Listen(patterns []string, handle MessageHandler) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.handle = handle
+	t.patterns = make(map[string]bool, len(patterns))
+	for _, p := range patterns {
+		t.patterns[p] = true
+	}
+	return nil
+}
+
+func (t *MemoryTransport) Close() error {
+	t.mu.Lock()
+	t.handle = nil
+	t.mu.Unlock()
+	return nil
+}
+
+// Send 同步投递一条消息并返回处理器的错误，失败的消息进入死信列表
+func (t *MemoryTransport) Send(ctx context.Context, msg *Message) error {
+	t.mu.Lock()
+	handle := t.handle
+	t.mu.Unlock()
+	if handle == nil {
+		return errors.New("memory transport is not listening")
+	}
+	err := handle(ctx, msg)
+	if err != nil {
+		t.mu.Lock()
+		t.deadLetters = append(t.deadLetters, msg)
+		t.mu.Unlock()
+	}
+	return err
+}
+
+// SendJSON 以 JSON 编码 v 后投递到 pattern
+func (t *MemoryTransport) SendJSON(ctx context.Context, pattern string, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return t.Send(ctx, &Message{Pattern: pattern, Value: data})
+}
+
+// DeadLetters 处理失败的消息
+func (t *MemoryTransport) DeadLetters() []*Message {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]*Message(nil), t.deadLetters...)
+}
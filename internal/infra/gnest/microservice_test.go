@@ -0,0 +1,169 @@
+package gnest_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"blog/internal/infra/gnest"
+)
+
+type noteCreatedDTO struct {
+	ID    int    `json:"id" validate:"required"`
+	Title string `json:"title"`
+}
+
+type internalOnlyGuard struct{}
+
+func (internalOnlyGuard) CanActivate(c *gin.Context) bool {
+	if c.GetHeader("X-Internal") == "" {
+		return gnest.Deny(c, gnest.Forbidden("internal messages only").WithCode("NOT_INTERNAL"))
+	}
+	return true
+}
+
+var errNoteRejected = errors.New("note rejected")
+
+type noteListener struct {
+	received []noteCreatedDTO
+	patterns []string
+}
+
+func (l *noteListener) Prefix() string { return "/notes" }
+func (l *noteListener) Routes(rg *gnest.RouterGroup) {
+	rg.MessagePattern("note.created", l.OnCreated)
+	rg.MessagePattern("note.audited", l.OnAudited, internalOnlyGuard{})
+}
+
+func (l *noteListener) OnCreated(c *gin.Context, dto *noteCreatedDTO) error {
+	if dto.Title == "reject" {
+		return errNoteRejected
+	}
+	l.received = append(l.received, *dto)
+	l.patterns = append(l.patterns, gnest.MessageOf(c).Pattern)
+	return nil
+}
+
+func (l *noteListener) OnAudited(msg *gnest.Message) error {
+	l.patterns = append(l.patterns, msg.Pattern)
+	return nil
+}
+
+func newMessageApp(t *testing.T, ctrl gnest.Controller) (*gnest.MemoryTransport, error) {
+	t.Helper()
+	transport := gnest.NewMemoryTransport()
+	app := gnest.NewTestingApp(&gnest.Module{Name: "Notes", Controllers: []gnest.Controller{ctrl}}).WithLifecycleHooks()
+	app.ConnectMicroservice(transport)
+	err := app.Compile()
+	t.Cleanup(func() { app.Shutdown(context.Background(), "test") })
+	return transport, err
+}
+
+func TestMessagePatternDispatch(t *testing.T) {
+	cases := []struct {
+		name     string
+		msg      *gnest.Message
+		wantErr  func(error) bool
+		wantDead bool
+	}{
+		{
+			name: "decodes and validates the dto",
+			msg:  &gnest.Message{Pattern: "note.created", Value: []byte(`{"id":1,"title":"hello"}`)},
+		},
+		{
+			name: "validation failure",
+			msg:  &gnest.Message{Pattern: "note.created", Value: []byte(`{"title":"no id"}`)},
+			wantErr: func(err error) bool {
+				var he *gnest.HttpException
+				return errors.As(err, &he) && he.Code == gnest.CodeValidationFailed
+			},
+			wantDead: true,
+		},
+		{
+			name: "malformed body",
+			msg:  &gnest.Message{Pattern: "note.created", Value: []byte(`{`)},
+			wantErr: func(err error) bool {
+				var he *gnest.HttpException
+				return errors.As(err, &he) && he.Status == 400
+			},
+			wantDead: true,
+		},
+		{
+			name:     "handler error",
+			msg:      &gnest.Message{Pattern: "note.created", Value: []byte(`{"id":2,"title":"reject"}`)},
+			wantErr:  func(err error) bool { return errors.Is(err, errNoteRejected) },
+			wantDead: true,
+		},
+		{
+			name: "guard rejection",
+			msg:  &gnest.Message{Pattern: "note.audited"},
+			wantErr: func(err error) bool {
+				var he *gnest.HttpException
+				return errors.As(err, &he) && he.Code == "NOT_INTERNAL"
+			},
+			wantDead: true,
+		},
+		{
+			name: "guard passes on headers",
+			msg:  &gnest.Message{Pattern: "note.audited", Headers: map[string]string{"X-Internal": "1"}},
+		},
+		{
+			name:     "unknown pattern",
+			msg:      &gnest.Message{Pattern: "note.deleted"},
+			wantErr:  func(err error) bool { return errors.Is(err, gnest.ErrNoMessageHandler) },
+			wantDead: true,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := &noteListener{}
+			transport, err := newMessageApp(t, ctrl)
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = transport.Send(context.Background(), tc.msg)
+			if tc.wantErr == nil && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tc.wantErr != nil && !tc.wantErr(err) {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			dead := transport.DeadLetters()
+			if tc.wantDead != (len(dead) == 1) || (tc.wantDead && dead[0] != tc.msg) {
+				t.Errorf("expected dead letter %v, got %d", tc.wantDead, len(dead))
+			}
+			if tc.wantErr == nil && (len(ctrl.patterns) != 1 || ctrl.patterns[0] != tc.msg.Pattern) {
+				t.Errorf("expected the handler to see pattern %q, got %v", tc.msg.Pattern, ctrl.patterns)
+			}
+		})
+	}
+
+	ctrl := &noteListener{}
+	transport, err := newMessageApp(t, ctrl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := transport.SendJSON(context.Background(), "note.created", noteCreatedDTO{ID: 3, Title: "json"}); err != nil {
+		t.Fatal(err)
+	}
+	if len(ctrl.received) != 1 || ctrl.received[0].ID != 3 || ctrl.received[0].Title != "json" {
+		t.Errorf("expected the decoded dto, got %+v", ctrl.received)
+	}
+}
+
+type twiceListener struct{ noteListener }
+
+func (l *twiceListener) Routes(rg *gnest.RouterGroup) {
+	rg.MessagePattern("note.created", l.OnCreated)
+	rg.MessagePattern("note.created", l.OnAudited)
+}
+
+func TestMessagePatternDeclaredTwice(t *testing.T) {
+	_, err := newMessageApp(t, &twiceListener{})
+	if err == nil || !strings.Contains(err.Error(), `"note.created" is declared twice`) {
+		t.Fatalf("expected a duplicate pattern error, got %v", err)
+	}
+}
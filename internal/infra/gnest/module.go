@@ -0,0 +1,286 @@
+package gnest
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ==========================================
+// 模块系统 (Module)
+// ==========================================
+
+// Module 对应 Nest 的 @Module：一个模块只能看到自己声明的 Provider
+// 以及所导入模块显式导出的 Provider
+type Module struct {
+	Name        string
+	Global      bool          // 全局模块：导出的 Provider 对所有模块可见 (如数据库、日志)
+	Imports     []*Module     // 依赖的其他模块
+	Providers   []interface{} // 本模块私有的 Provider
+	Controllers []Controller  // 本模块的控制器，初始化时挂载路由
+	Exports     []interface{} // 对外暴露：Provider 实例 / typed nil / reflect.Type / Provider / 需要重新导出的 *Module
+}
+
+// Controller 控制器声明自身的路由前缀与路由表
+type Controller interface {
+	Prefix() string
+	Routes(rg *RouterGroup)
+}
+
+// moduleRef 是 Module 在启动阶段编译后的运行时结构
+type moduleRef struct {
+	def         *Module
+	providers   map[token]*providerDef // 本模块声明的 Provider
+	exports     map[token]*providerDef // 对外导出的 Provider
+	imports     []*moduleRef
+	declared    []*providerDef // 按声明顺序排列的 Provider
+	controllers []*providerDef // 控制器同样走注入，但不能被其他 Provider 依赖
+}
+
+func (m *moduleRef) name() string {
+	if m.def.Name != "" {
+		return m.def.Name
+	}
+	return fmt.Sprintf("%p", m.def)
+}
+
+// where 用于错误信息中标注出错的模块
+func (m *moduleRef) where() string {
+	if m == nil {
+		return ""
+	}
+	return " in module " + m.name()
+}
+
+// NewFromModule 以根模块创建应用，模块装配中的所有问题都会在这里一次性返回
+func NewFromModule(root *Module) (*GnestApp, error) {
+	app := New()
+	if err := app.registerModule(root); err != nil {
+		return nil, err
+	}
+	return app, nil
+}
+
+// registerModule 编译模块树：先处理导入，再校验导出，最后按模块作用域注入依赖
+func (app *GnestApp) registerModule(root *Module) error {
+	refs := make(map[*Module]*moduleRef)
+	visiting := make(map[*Module]bool)
+	var order []*moduleRef
+	var errs []error
+
+	var visit func(m *Module, stack []string) *moduleRef
+	visit = func(m *Module, stack []string) *moduleRef {
+		if ref, ok := refs[m]; ok {
+			return ref
+		}
+		name := m.Name
+		if name == "" {
+			name = fmt.Sprintf("%p", m)
+		}
+		if visiting[m] {
+			errs = append(errs, fmt.Errorf("circular module import: %s", strings.Join(append(stack, name), " -> ")))
+			return nil
+		}
+		visiting[m] = true
+		defer delete(visiting, m)
+
+		ref := &moduleRef{
+			def:       m,
+			providers: make(map[token]*providerDef),
+			exports:   make(map[token]*providerDef),
+		}
+		for _, im := range m.Imports {
+			if im == nil {
+				errs = append(errs, fmt.Errorf("module %s: nil import", name))
+				continue
+			}
+			if imRef := visit(im, append(stack, name)); imRef != nil {
+				ref.imports = append(ref.imports, imRef)
+			}
+		}
+		errs = append(errs, app.compileModule(ref)...)
+		refs[m] = ref
+		order = append(order, ref)
+		return ref
+	}
+	visit(root, nil)
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+
+	// 所有模块编译完成后再构建实例，保证全局模块的导出已就绪
+	var defs []*providerDef
+	for _, ref := range order {
+		defs = append(defs, ref.declared...)
+		defs = append(defs, ref.controllers...)
+	}
+	if err := app.resolveAll(defs); err != nil {
+		return err
+	}
+	app.modules = append(app.modules, order...)
+	return nil
+}
+
+// compileModule 登记 Provider、检查导入冲突并解析导出表
+func (app *GnestApp) compileModule(ref *moduleRef) []error {
+	var errs []error
+	name := ref.name()
+
+	for _, p := range ref.def.Providers {
+		def, err := newProviderDef(p, ref)
+		if err == nil {
+			err = app.applyOverride(def)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("module %s: %w", name, err))
+			continue
+		}
+		if _, dup := ref.providers[def.tok]; dup {
+			errs = append(errs, fmt.Errorf("module %s: duplicate provider %v", name, def.tok))
+			continue
+		}
+		ref.providers[def.tok] = def
+		ref.declared = append(ref.declared, def)
+	}
+	for _, c := range ref.def.Controllers {
+		def, err := newProviderDef(c, ref)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("module %s: controller: %w", name, err))
+			continue
+		}
+		ref.controllers = append(ref.controllers, def)
+	}
+
+	// 两个导入模块导出同一类型的不同实例时，注入目标不明确
+	seen := make(map[token]*moduleRef)
+	for _, im := range ref.imports {
+		for t, def := range im.exports {
+			if prev, ok := seen[t]; ok && prev.exports[t] != def {
+				errs = append(errs, fmt.Errorf("module %s: %v is exported by both %s and %s", name, t, prev.name(), im.name()))
+				continue
+			}
+			seen[t] = im
+		}
+	}
+
+	for _, e := range ref.def.Exports {
+		if m, ok := e.(*Module); ok {
+			imRef := ref.findImport(m)
+			if imRef == nil {
+				errs = append(errs, fmt.Errorf("module %s: re-exports %s, which it does not import", name, m.Name))
+				continue
+			}
+			for t, def := range imRef.exports {
+				ref.exports[t] = def
+			}
+			continue
+		}
+		t := tokenOf(e)
+		if t.typ == nil {
+			errs = append(errs, fmt.Errorf("module %s: nil export", name))
+			continue
+		}
+		if _, dup := ref.exports[t]; dup {
+			errs = append(errs, fmt.Errorf("module %s: duplicate export %v", name, t))
+			continue
+		}
+		if def, ok := ref.providers[t]; ok {
+			ref.exports[t] = def
+		} else if im, ok := seen[t]; ok {
+			ref.exports[t] = im.exports[t]
+		} else {
+			errs = append(errs, fmt.Errorf("module %s: exports %v, which it neither provides nor imports", name, t))
+		}
+	}
+
+	if ref.def.Global {
+		for t, def := range ref.exports {
+			if prev, ok := app.providers[t]; ok && prev != def {
+				errs = append(errs, fmt.Errorf("module %s: global export %v conflicts with an existing provider", name, t))
+				continue
+			}
+			app.providers[t] = def
+		}
+	}
+	return errs
+}
+
+func (m *moduleRef) findImport(def *Module) *moduleRef {
+	for _, im := range m.imports {
+		if im.def == def {
+			return im
+		}
+	}
+	return nil
+}
+
+// lookup 在模块作用域内查找：本模块 -> 导入模块的导出 -> 全局
+func (m *moduleRef) lookup(app *GnestApp, tok token) (*providerDef, bool) {
+	if def, ok := m.providers[tok]; ok {
+		return def, true
+	}
+	for _, im := range m.imports {
+		if def, ok := im.exports[tok]; ok {
+			return def, true
+		}
+	}
+	def, ok := app.providers[tok]
+	return def, ok
+}
+
+// tokenOf 将 Provider / 导出声明统一为令牌，支持实例、typed nil、reflect.Type 与 Provider
+func tokenOf(e interface{}) token {
+	switch v := e.(type) {
+	case Provider:
+		return v.tok
+	case reflect.Type:
+		return token{typ: v}
+	}
+	return token{typ: reflect.TypeOf(e)}
+}
+
+// Init 构建根容器中的 Provider，挂载所有模块控制器的路由与 WebSocket 网关；
+// 需在注册全局增强器之后调用，ListenAndServe 会自动调用。
+// 只有成功后才标记为已初始化，失败后再次调用返回同一个错误，不会在半初始化的应用上继续
+func (app *GnestApp) Init() error {
+	if app.initialized {
+		return nil
+	}
+	if app.initErr != nil {
+		return app.initErr
+	}
+	app.initErr = app.init()
+	app.initialized = app.initErr == nil
+	return app.initErr
+}
+
+func (app *GnestApp) init() error {
+	if len(app.errs) > 0 {
+		return errors.Join(app.errs...)
+	}
+	if err := app.resolveAll(app.rootDefs); err != nil {
+		return err
+	}
+	if err := app.loadVersioning(); err != nil {
+		return err
+	}
+	for _, ref := range app.modules {
+		for _, def := range ref.controllers {
+			// 请求作用域的控制器没有单例，路由表由注册的原型声明
+			c := def.value.Interface().(Controller)
+			rg := app.Group(c.Prefix())
+			rg.module = ref
+			rg.controller = def
+			c.Routes(rg)
+		}
+	}
+	// 挂载路由时发现的错误 (如重复的消息模式 / 版本)
+	errs := app.errs
+	for _, m := range app.gateways {
+		if err := app.mountGateway(m); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
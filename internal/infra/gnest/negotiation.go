@@ -0,0 +1,416 @@
+package gnest
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vmihailenco/msgpack/v5"
+	"gopkg.in/yaml.v3"
+)
+
+// ==========================================
+// 内容协商 (Content Negotiation)
+// ==========================================
+
+// 处理器返回的结构体 / map / 切片按请求的 Accept 头选择序列化器输出，
+// 启用 EnableFormatQuery 后查询参数优先于 Accept：
+//
+//	GET /posts  Accept: application/xml
+//	GET /posts?format=csv  (app.EnableFormatQuery("format"))
+//
+// 各序列化器的权重取最精确匹配项的 q 值，权重相同时按注册顺序 (JSON 优先)；
+// 浏览器式的 Accept (*/* 与其他类型并列，如 text/html,...,*/*;q=0.8) 不代表真实偏好，优先使用第一个序列化器。
+// 内置 JSON、XML、YAML、MessagePack 与 CSV (仅切片)，没有可用的序列化器时返回 406。
+// YAML、MessagePack 与 CSV 的字段名取 json 标签，与 JSON 输出保持一致；XML 沿用 xml 标签
+
+// Serializer 响应序列化器，通过 app.RegisterSerializer 扩展
+type Serializer interface {
+	Format() string       // EnableFormatQuery 参数的取值，如 "json"
+	MediaTypes() []string // 可匹配的媒体类型，首个作为响应的 Content-Type
+	Marshal(v interface{}) ([]byte, error)
+}
+
+// ErrUnsupportedValue 序列化器无法表示该值 (如 CSV 遇到非切片) 时返回，协商继续尝试下一个
+var ErrUnsupportedValue = errors.New("value is not supported by serializer")
+
+func defaultSerializers() []Serializer {
+	return []Serializer{
+		jsonSerializer{},
+		xmlSerializer{},
+		yamlSerializer{},
+		msgpackSerializer{},
+		csvSerializer{},
+	}
+}
+
+// RegisterSerializer 注册序列化器，Format 相同时替换内置实现；未指定 Accept 时使用第一个 (JSON)
+func (app *GnestApp) RegisterSerializer(ss ...Serializer) *GnestApp {
+outer:
+	for _, s := range ss {
+		for i, x := range app.serializers {
+			if x.Format() == s.Format() {
+				app.serializers[i] = s
+				continue outer
+			}
+		}
+		app.serializers = append(app.serializers, s)
+	}
+	return app
+}
+
+// EnableFormatQuery 允许以查询参数 (如 "format") 指定序列化器并覆盖 Accept，默认关闭；
+// 启用后该参数在所有路由上保留，与路由自身的同名参数冲突时应换用其他名称
+func (app *GnestApp) EnableFormatQuery(name string) *GnestApp {
+	app.formatQuery = name
+	return app
+}
+
+// negotiate 按格式参数 / Accept 选择序列化器并写出响应
+func (app *GnestApp) negotiate(c *gin.Context, res interface{}) error {
+	c.Writer.Header().Add("Vary", "Accept")
+
+	if app.formatQuery != "" {
+		if format := c.Query(app.formatQuery); format != "" {
+			for _, s := range app.serializers {
+				if strings.EqualFold(s.Format(), format) {
+					return writeSerialized(c, s, res, true)
+				}
+			}
+			return NotAcceptable(fmt.Sprintf("unsupported format %q", format)).WithDetails(app.serializerFormats())
+		}
+	}
+
+	header := c.GetHeader("Accept")
+	for _, s := range app.rankSerializers(header) {
+		if err := writeSerialized(c, s, res, false); !errors.Is(err, ErrUnsupportedValue) {
+			return err
+		}
+	}
+	return NotAcceptable("no acceptable representation for " + header).WithDetails(app.serializerFormats())
+}
+
+// rankSerializers 返回 Accept 可接受的序列化器，按权重降序、注册顺序稳定排列
+func (app *GnestApp) rankSerializers(header string) []Serializer {
+	ranges := parseAccept(header)
+	browser := len(ranges) > 1 && hasFullWildcard(ranges)
+	type ranked struct {
+		s Serializer
+		q float64
+	}
+	var rs []ranked
+	for i, s := range app.serializers {
+		q := serializerQuality(s, ranges)
+		if q <= 0 {
+			continue
+		}
+		if browser && i == 0 {
+			q = 2 // 高于任何合法 q 值
+		}
+		rs = append(rs, ranked{s, q})
+	}
+	sort.SliceStable(rs, func(i, j int) bool { return rs[i].q > rs[j].q })
+	out := make([]Serializer, len(rs))
+	for i, r := range rs {
+		out[i] = r.s
+	}
+	return out
+}
+
+// writeSerialized 写出响应；explicit 为格式参数指定时，不支持的值直接返回 406
+func writeSerialized(c *gin.Context, s Serializer, res interface{}, explicit bool) error {
+	data, err := s.Marshal(res)
+	if errors.Is(err, ErrUnsupportedValue) && explicit {
+		return NotAcceptable(fmt.Sprintf("format %q cannot represent %T", s.Format(), res)).WithCause(err)
+	}
+	if err != nil {
+		return err
+	}
+	c.Data(http.StatusOK, s.MediaTypes()[0], data)
+	return nil
+}
+
+func (app *GnestApp) serializerFormats() []string {
+	formats := make([]string, len(app.serializers))
+	for i, s := range app.serializers {
+		formats[i] = s.Format()
+	}
+	return formats
+}
+
+// mediaRange Accept 头中的一项
+type mediaRange struct {
+	typ, sub string
+	q        float64
+}
+
+// parseAccept 解析 Accept 头并按 q 值、精确度降序排列，空值视为 */*
+func parseAccept(header string) []mediaRange {
+	var ranges []mediaRange
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(part, ";")
+		typ, sub, _ := strings.Cut(strings.ToLower(strings.TrimSpace(fields[0])), "/")
+		if typ == "" {
+			continue
+		}
+		if sub == "" {
+			sub = "*"
+		}
+		r := mediaRange{typ: typ, sub: sub, q: 1}
+		for _, param := range fields[1:] {
+			k, v, _ := strings.Cut(strings.TrimSpace(param), "=")
+			if strings.EqualFold(k, "q") {
+				if q, err := strconv.ParseFloat(v, 64); err == nil {
+					r.q = q
+				}
+			}
+		}
+		ranges = append(ranges, r)
+	}
+	if len(ranges) == 0 {
+		return []mediaRange{{typ: "*", sub: "*", q: 1}}
+	}
+	sort.SliceStable(ranges, func(i, j int) bool {
+		if ranges[i].q != ranges[j].q {
+			return ranges[i].q > ranges[j].q
+		}
+		return ranges[i].specificity() > ranges[j].specificity()
+	})
+	return ranges
+}
+
+func (r mediaRange) specificity() int {
+	switch {
+	case r.typ == "*":
+		return 0
+	case r.sub == "*":
+		return 1
+	}
+	return 2
+}
+
+func (r mediaRange) matches(mediaType string) bool {
+	typ, sub, _ := strings.Cut(mediaType, "/")
+	return (r.typ == "*" || r.typ == typ) && (r.sub == "*" || r.sub == sub)
+}
+
+// serializerQuality 取 s 各媒体类型中最高的权重；每个媒体类型的权重由匹配它的最精确的项决定，
+// 任一媒体类型被精确地以 q=0 排除时整个序列化器不可用 (如 application/json;q=0 同时排除 text/json)。
+// 未匹配时返回 0
+func serializerQuality(s Serializer, ranges []mediaRange) float64 {
+	best := 0.0
+	for _, mt := range s.MediaTypes() {
+		mt, _, _ = strings.Cut(strings.ToLower(mt), ";")
+		mt = strings.TrimSpace(mt)
+		spec := -1
+		q := 0.0
+		// ranges 已按 q 降序排列，同等精确度取先出现 (q 更高) 的项
+		for _, r := range ranges {
+			if r.matches(mt) && r.specificity() > spec {
+				spec, q = r.specificity(), r.q
+			}
+		}
+		if spec == 2 && q == 0 {
+			return 0
+		}
+		if q > best {
+			best = q
+		}
+	}
+	return best
+}
+
+func hasFullWildcard(ranges []mediaRange) bool {
+	for _, r := range ranges {
+		if r.typ == "*" && r.q > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// ==========================================
+// 内置序列化器
+// ==========================================
+
+type jsonSerializer struct{}
+
+func (jsonSerializer) Format() string { return "json" }
+func (jsonSerializer) MediaTypes() []string {
+	return []string{"application/json; charset=utf-8", "text/json"}
+}
+func (jsonSerializer) Marshal(v interface{}) ([]byte, error) { return json.Marshal(v) }
+
+// xmlSerializer 切片包裹在 <items> 根元素中；map 无法表示为 XML
+type xmlSerializer struct{}
+
+func (xmlSerializer) Format() string { return "xml" }
+func (xmlSerializer) MediaTypes() []string {
+	return []string{"application/xml; charset=utf-8", "text/xml"}
+}
+func (xmlSerializer) Marshal(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	enc := xml.NewEncoder(&buf)
+	var err error
+	if rv := reflect.Indirect(reflect.ValueOf(v)); isListKind(rv) {
+		items := xml.StartElement{Name: xml.Name{Local: "items"}}
+		err = enc.EncodeToken(items)
+		for i := 0; err == nil && i < rv.Len(); i++ {
+			err = enc.Encode(rv.Index(i).Interface())
+		}
+		if err == nil {
+			err = enc.EncodeToken(items.End())
+		}
+	} else {
+		err = enc.Encode(v)
+	}
+	if err == nil {
+		err = enc.Flush()
+	}
+	var ute *xml.UnsupportedTypeError
+	if errors.As(err, &ute) {
+		return nil, fmt.Errorf("%w: %v", ErrUnsupportedValue, err)
+	}
+	return buf.Bytes(), err
+}
+
+// yamlSerializer 经由 JSON 转换，字段名与顺序和 JSON 输出一致
+type yamlSerializer struct{}
+
+func (yamlSerializer) Format() string { return "yaml" }
+func (yamlSerializer) MediaTypes() []string {
+	return []string{"application/yaml; charset=utf-8", "application/x-yaml", "text/yaml"}
+}
+func (yamlSerializer) Marshal(v interface{}) ([]byte, error) {
+	node, err := jsonNode(v)
+	if err != nil {
+		return nil, err
+	}
+	return yaml.Marshal(node)
+}
+
+type msgpackSerializer struct{}
+
+func (msgpackSerializer) Format() string { return "msgpack" }
+func (msgpackSerializer) MediaTypes() []string {
+	return []string{"application/msgpack", "application/x-msgpack", "application/vnd.msgpack"}
+}
+func (msgpackSerializer) Marshal(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := msgpack.NewEncoder(&buf)
+	enc.SetCustomStructTag("json")
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// csvSerializer 只支持切片：对象的键并集作为表头，嵌套值以 JSON 写入单元格；信封只输出 data
+type csvSerializer struct{}
+
+func (csvSerializer) Format() string { return "csv" }
+func (csvSerializer) MediaTypes() []string {
+	return []string{"text/csv; charset=utf-8", "application/csv"}
+}
+func (csvSerializer) Marshal(v interface{}) ([]byte, error) {
+	if e, ok := v.(Envelope); ok {
+		v = e.Data
+	}
+	if !isListKind(reflect.Indirect(reflect.ValueOf(v))) {
+		return nil, fmt.Errorf("%w: csv requires a slice, got %T", ErrUnsupportedValue, v)
+	}
+	node, err := jsonNode(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var header []string
+	columns := make(map[string]int)
+	rows := make([][]string, len(node.Content))
+	for i, item := range node.Content {
+		if item.Kind != yaml.MappingNode {
+			if _, ok := columns["value"]; !ok {
+				columns["value"] = len(header)
+				header = append(header, "value")
+			}
+			rows[i] = setCell(rows[i], columns["value"], item)
+			continue
+		}
+		for j := 0; j+1 < len(item.Content); j += 2 {
+			key := item.Content[j].Value
+			if _, ok := columns[key]; !ok {
+				columns[key] = len(header)
+				header = append(header, key)
+			}
+			rows[i] = setCell(rows[i], columns[key], item.Content[j+1])
+		}
+	}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if len(header) > 0 {
+		_ = w.Write(header)
+	}
+	for _, row := range rows {
+		_ = w.Write(append(row, make([]string, len(header)-len(row))...))
+	}
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
+
+func setCell(row []string, i int, n *yaml.Node) []string {
+	for len(row) <= i {
+		row = append(row, "")
+	}
+	switch {
+	case n.Kind == yaml.ScalarNode && n.Tag == "!!null":
+	case n.Kind == yaml.ScalarNode:
+		row[i] = n.Value
+	default:
+		var x interface{}
+		if err := n.Decode(&x); err == nil {
+			data, _ := json.Marshal(x)
+			row[i] = string(data)
+		}
+	}
+	return row
+}
+
+// jsonNode 将 v 按 JSON 编码后解析为有序的 YAML 节点，沿用 json 标签与 MarshalJSON
+func jsonNode(v interface{}) (*yaml.Node, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	node := doc.Content[0]
+	resetStyle(node)
+	return node, nil
+}
+
+// resetStyle 去掉 JSON 解析留下的流式 / 引号风格，输出块风格的 YAML；
+// 形如 "true" / "123" 的字符串仍会被 yaml 库自动加引号
+func resetStyle(n *yaml.Node) {
+	n.Style = 0
+	for _, c := range n.Content {
+		resetStyle(c)
+	}
+}
+
+func isListKind(rv reflect.Value) bool {
+	k := rv.Kind()
+	return (k == reflect.Slice || k == reflect.Array) && rv.Type().Elem().Kind() != reflect.Uint8
+}
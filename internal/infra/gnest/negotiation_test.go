@@ -0,0 +1,57 @@
+package gnest_test
+
+import (
+	"strings"
+	"testing"
+
+	"blog/internal/infra/gnest"
+)
+
+type report struct {
+	Format string `json:"format" xml:"format"`
+}
+
+func newReportApp(t *testing.T, formatQuery string) *gnest.TestingApp {
+	app := gnest.NewTestingApp()
+	if formatQuery != "" {
+		app.EnableFormatQuery(formatQuery)
+	}
+	app.GET("/reports", func(format string) report { return report{Format: format} }, gnest.Query[string]{"format"})
+	if err := app.Compile(); err != nil {
+		t.Fatal(err)
+	}
+	return app
+}
+
+func TestNegotiationPrefersJSON(t *testing.T) {
+	app := newReportApp(t, "")
+	cases := []struct{ accept, want string }{
+		{"", "application/json"},
+		{"text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8", "application/json"},
+		{"application/xml, application/json", "application/json"},
+		{"application/xml", "application/xml"},
+		{"application/json;q=0.5, application/xml", "application/xml"},
+		{"*/*, application/json;q=0", "application/xml"},
+	}
+	for _, tc := range cases {
+		res := app.Client(t).GET("/reports").Header("Accept", tc.accept).ExpectStatus(200).Response()
+		if got := res.Header().Get("Content-Type"); !strings.HasPrefix(got, tc.want) {
+			t.Errorf("Accept %q: expected %s, got %s", tc.accept, tc.want, got)
+		}
+	}
+}
+
+func TestFormatQueryIsOptIn(t *testing.T) {
+	var got report
+	newReportApp(t, "").Client(t).GET("/reports").Query("format", "pdf").ExpectStatus(200).DecodeInto(&got)
+	if got.Format != "pdf" {
+		t.Errorf("expected the route to receive its own format parameter, got %+v", got)
+	}
+
+	app := newReportApp(t, "as")
+	res := app.Client(t).GET("/reports").Query("as", "yaml").Query("format", "pdf").ExpectStatus(200).Response()
+	if ct := res.Header().Get("Content-Type"); !strings.HasPrefix(ct, "application/yaml") {
+		t.Errorf("expected YAML, got %s", ct)
+	}
+	app.Client(t).GET("/reports").Query("as", "pdf").ExpectStatus(406)
+}
@@ -0,0 +1,714 @@
+package gnest
+
+import (
+	_ "embed"
+	"encoding/json"
+	"html/template"
+	"mime/multipart"
+	"net/http"
+	"path"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ==========================================
+// OpenAPI 3.1 文档 (Route Registry & Schema)
+// ==========================================
+
+// RouteInfo 路由注册表中的一项，由 RouterGroup.Handle 在注册时记录
+type RouteInfo struct {
+	Method    string
+	Path      string       // gin 格式的完整路径，如 /auth/users/:id
+	Handler   reflect.Type // 处理函数签名，参数类型用于生成请求参数与请求体
+	Params    []*ParamInfo // 与处理函数参数一一对应，参数标记绑定的参数非 nil
+	Operation *Operation   // ApiOperation 提供的描述，可能为 nil
+	Tags      []string     // 路由组上声明的标签
+	Version   string       // 启用接口版本时路由所属的版本，版本无关为空
+}
+
+// Operation OpenAPI 接口描述，通过 ApiOperation 作为方法级增强器传入
+type Operation struct {
+	Summary     string
+	Description string
+	Tags        []string
+	OperationID string
+	Deprecated  bool
+	Responses   []ApiResponse
+}
+
+// ApiResponse 描述一种响应：状态码、说明与响应体类型 (传入该类型的零值或指针即可)
+type ApiResponse struct {
+	Status      int
+	Description string
+	Type        interface{}
+}
+
+// ApiOperation 方法级增强器：为路由补充摘要、标签与响应说明
+func ApiOperation(op Operation) *Operation {
+	return &op
+}
+
+// ApiTags 为路由组下的所有接口声明标签
+func (rg *RouterGroup) ApiTags(tags ...string) *RouterGroup {
+	rg.tags = append(rg.tags, tags...)
+	return rg
+}
+
+// Routes 返回按注册顺序排列的路由表
+func (app *GnestApp) Routes() []RouteInfo {
+	return append([]RouteInfo(nil), app.routes...)
+}
+
+// OpenAPIConfig 文档基本信息与挂载路径
+type OpenAPIConfig struct {
+	Title       string
+	Version     string
+	Description string
+	SpecPath    string // 默认 /openapi.json
+	DocsPath    string // 默认 /docs
+	// AssetsURL Swagger UI 静态资源 (swagger-ui.css / swagger-ui-bundle.js) 所在目录，
+	// 默认从 jsDelivr CDN 加载，因此浏览 /docs 需要访问外网；内网部署时可将 swagger-ui-dist
+	// 放到本地并用 app.Static 托管，如 "/static/swagger-ui"
+	AssetsURL string
+	// APIVersion 请求头 / 媒体类型版本下同一路径的各版本无法放进一份文档，每份文档只描述一个版本
+	// (及版本无关的路由)。为空时取 DefaultVersion，仍为空时取最新版本；
+	// UseOpenAPI 挂载的 SpecPath 支持 ?version= 选择版本，Swagger UI 提供版本切换。URI 版本下不需要
+	APIVersion string
+}
+
+// defaultSwaggerAssets Swagger UI 默认的 CDN 地址
+const defaultSwaggerAssets = "https://cdn.jsdelivr.net/npm/swagger-ui-dist@5"
+
+// OpenAPIDocument 是 OpenAPI 3.1 文档中用到的子集
+type OpenAPIDocument struct {
+	OpenAPI    string                                  `json:"openapi"`
+	Info       OpenAPIInfo                             `json:"info"`
+	Paths      map[string]map[string]*OpenAPIOperation `json:"paths"`
+	Components OpenAPIComponents                       `json:"components"`
+}
+
+type OpenAPIInfo struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+type OpenAPIComponents struct {
+	Schemas map[string]*Schema `json:"schemas,omitempty"`
+}
+
+type OpenAPIOperation struct {
+	OperationID string                      `json:"operationId,omitempty"`
+	Summary     string                      `json:"summary,omitempty"`
+	Description string                      `json:"description,omitempty"`
+	Tags        []string                    `json:"tags,omitempty"`
+	Deprecated  bool                        `json:"deprecated,omitempty"`
+	Parameters  []*OpenAPIParameter         `json:"parameters,omitempty"`
+	RequestBody *OpenAPIRequestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*OpenAPIResponse `json:"responses"`
+}
+
+type OpenAPIParameter struct {
+	Name     string  `json:"name"`
+	In       string  `json:"in"` // path / query / header / cookie
+	Required bool    `json:"required,omitempty"`
+	Schema   *Schema `json:"schema"`
+}
+
+type OpenAPIRequestBody struct {
+	Required bool                         `json:"required,omitempty"`
+	Content  map[string]*OpenAPIMediaType `json:"content"`
+}
+
+type OpenAPIResponse struct {
+	Description string                       `json:"description"`
+	Content     map[string]*OpenAPIMediaType `json:"content,omitempty"`
+}
+
+type OpenAPIMediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// Schema JSON Schema (2020-12) 的常用子集
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Enum                 []interface{}      `json:"enum,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	MinItems             *int               `json:"minItems,omitempty"`
+	MaxItems             *int               `json:"maxItems,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	ExclusiveMinimum     *float64           `json:"exclusiveMinimum,omitempty"`
+	ExclusiveMaximum     *float64           `json:"exclusiveMaximum,omitempty"`
+}
+
+//go:embed swagger.html
+var swaggerHTML string
+
+var swaggerTemplate = template.Must(template.New("swagger").Parse(swaggerHTML))
+
+// UseOpenAPI 挂载文档：SpecPath 输出 JSON，DocsPath 提供 Swagger UI。
+// 文档在每次请求时根据路由表生成，因此 Init 之后挂载的模块路由同样会出现
+func (app *GnestApp) UseOpenAPI(cfg OpenAPIConfig) *GnestApp {
+	if cfg.SpecPath == "" {
+		cfg.SpecPath = "/openapi.json"
+	}
+	if cfg.DocsPath == "" {
+		cfg.DocsPath = "/docs"
+	}
+	if cfg.AssetsURL == "" {
+		cfg.AssetsURL = defaultSwaggerAssets
+	}
+	assets := strings.TrimSuffix(cfg.AssetsURL, "/")
+	app.Engine.GET(cfg.SpecPath, func(c *gin.Context) {
+		cfg := cfg
+		if v := c.Query("version"); v != "" {
+			cfg.APIVersion = v
+		}
+		c.JSON(http.StatusOK, app.OpenAPI(cfg))
+	})
+	app.Engine.GET(cfg.DocsPath, func(c *gin.Context) {
+		// 每个版本一份文档，首个为默认展示的版本
+		var specs []gin.H
+		if current := app.documentedVersion(cfg); current != "" {
+			specs = append(specs, gin.H{"name": "v" + current, "url": cfg.SpecPath + "?version=" + current})
+			for _, v := range app.apiVersions() {
+				if v != current {
+					specs = append(specs, gin.H{"name": "v" + v, "url": cfg.SpecPath + "?version=" + v})
+				}
+			}
+		}
+		c.Header("Content-Type", "text/html; charset=utf-8")
+		_ = swaggerTemplate.Execute(c.Writer, gin.H{"Title": cfg.Title, "SpecURL": cfg.SpecPath, "Specs": specs, "AssetsURL": assets})
+	})
+	return app
+}
+
+// OpenAPI 根据当前路由表生成文档
+func (app *GnestApp) OpenAPI(cfg OpenAPIConfig) *OpenAPIDocument {
+	gen := &schemaGen{schemas: make(map[string]*Schema), names: make(map[reflect.Type]string)}
+	doc := &OpenAPIDocument{
+		OpenAPI: "3.1.0",
+		Info:    OpenAPIInfo{Title: cfg.Title, Version: cfg.Version, Description: cfg.Description},
+		Paths:   make(map[string]map[string]*OpenAPIOperation),
+	}
+	version := app.documentedVersion(cfg)
+	for _, r := range app.routes {
+		if version != "" && r.Version != "" && r.Version != version {
+			continue
+		}
+		p := openapiPath(r.Path)
+		if doc.Paths[p] == nil {
+			doc.Paths[p] = make(map[string]*OpenAPIOperation)
+		}
+		op := gen.operation(r)
+		if version != "" && r.Version != "" {
+			app.versioning.describe(op, r.Version)
+		}
+		doc.Paths[p][strings.ToLower(r.Method)] = op
+	}
+	doc.Components.Schemas = gen.schemas
+	return doc
+}
+
+// documentedVersion 文档描述的版本；未启用版本、URI 版本或没有版本化的路由时为空，输出全部路由
+func (app *GnestApp) documentedVersion(cfg OpenAPIConfig) string {
+	v := app.versioning
+	if v == nil || v.strategy.kind == uriVersioning {
+		return ""
+	}
+	versions := app.apiVersions()
+	if len(versions) == 0 {
+		return ""
+	}
+	if cfg.APIVersion != "" {
+		return strings.TrimPrefix(cfg.APIVersion, "v")
+	}
+	if v.opts != nil && v.opts.DefaultVersion != "" {
+		return v.opts.DefaultVersion
+	}
+	return versions[len(versions)-1]
+}
+
+// apiVersions 路由表中出现的版本，按版本号升序
+func (app *GnestApp) apiVersions() []string {
+	seen := make(map[string]bool)
+	var versions []string
+	for _, r := range app.routes {
+		if r.Version != "" && !seen[r.Version] {
+			seen[r.Version] = true
+			versions = append(versions, r.Version)
+		}
+	}
+	sort.Slice(versions, func(i, j int) bool { return compareVersions(versions[i], versions[j]) < 0 })
+	return versions
+}
+
+var ginParamPattern = regexp.MustCompile(`[:*]([^/]+)`)
+
+// openapiPath 将 /users/:id 转为 /users/{id}
+func openapiPath(p string) string {
+	return ginParamPattern.ReplaceAllString(p, "{$1}")
+}
+
+func joinPaths(base, rel string) string {
+	if rel == "" {
+		return base
+	}
+	joined := path.Join(base, rel)
+	if strings.HasSuffix(rel, "/") && !strings.HasSuffix(joined, "/") {
+		joined += "/"
+	}
+	return joined
+}
+
+// ------------------------------------------
+// 文档生成
+// ------------------------------------------
+
+type schemaGen struct {
+	schemas map[string]*Schema
+	names   map[reflect.Type]string
+}
+
+var (
+	timeType       = reflect.TypeOf(time.Time{})
+	fileHeaderType = reflect.TypeOf((*multipart.FileHeader)(nil))
+	marshalerType  = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+)
+
+func (g *schemaGen) operation(r RouteInfo) *OpenAPIOperation {
+	op := &OpenAPIOperation{
+		OperationID: operationID(r.Method, r.Path),
+		Tags:        r.Tags,
+		Responses:   make(map[string]*OpenAPIResponse),
+	}
+	if r.Operation != nil {
+		op.Summary = r.Operation.Summary
+		op.Description = r.Operation.Description
+		op.Deprecated = r.Operation.Deprecated
+		if r.Operation.OperationID != "" {
+			op.OperationID = r.Operation.OperationID
+		}
+		if len(r.Operation.Tags) > 0 {
+			op.Tags = r.Operation.Tags
+		}
+	}
+
+	declared := make(map[string]bool)
+	for i := 0; i < r.Handler.NumIn(); i++ {
+		if i < len(r.Params) && r.Params[i] != nil {
+			g.describeMarker(op, *r.Params[i], declared)
+			continue
+		}
+		g.describeParam(op, r.Method, r.Handler.In(i), declared)
+	}
+	// 路径中出现但没有 DTO 字段描述的参数，按字符串补齐
+	for _, m := range ginParamPattern.FindAllStringSubmatch(r.Path, -1) {
+		if !declared["path:"+m[1]] {
+			op.Parameters = append(op.Parameters, &OpenAPIParameter{Name: m[1], In: "path", Required: true, Schema: &Schema{Type: "string"}})
+		}
+	}
+
+	if r.Operation != nil {
+		for _, resp := range r.Operation.Responses {
+			out := &OpenAPIResponse{Description: resp.Description}
+			if out.Description == "" {
+				out.Description = http.StatusText(resp.Status)
+			}
+			if resp.Type != nil {
+				out.Content = map[string]*OpenAPIMediaType{"application/json": {Schema: g.schemaOf(reflect.TypeOf(resp.Type))}}
+			}
+			op.Responses[strconv.Itoa(resp.Status)] = out
+		}
+	}
+	if len(op.Responses) == 0 {
+		out := &OpenAPIResponse{Description: "OK"}
+		if r.Handler.NumOut() > 0 {
+			if rt := r.Handler.Out(0); rt.Kind() != reflect.Interface {
+				out.Content = map[string]*OpenAPIMediaType{"application/json": {Schema: g.schemaOf(rt)}}
+			}
+		}
+		op.Responses["200"] = out
+	}
+	return op
+}
+
+// describeParam 将处理函数的一个参数映射为 OpenAPI 参数 / 请求体
+func (g *schemaGen) describeParam(op *OpenAPIOperation, method string, t reflect.Type, declared map[string]bool) {
+	if t == fileHeaderType || t == reflect.SliceOf(fileHeaderType) {
+		g.addFileBody(op, t)
+		return
+	}
+	if t.Kind() != reflect.Ptr || t.Elem().Kind() != reflect.Struct {
+		return // *gin.Context、Provider 等框架注入的参数不出现在文档中
+	}
+	st := t.Elem()
+	if st.PkgPath() == "net/http" || st.PkgPath() == "github.com/gin-gonic/gin" {
+		return
+	}
+
+	body := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	inQuery := method == http.MethodGet || method == http.MethodDelete || method == http.MethodHead
+	walkFields(st, func(f reflect.StructField) {
+		rules := parseRules(f)
+		schema := g.schemaOf(f.Type)
+		applyRules(schema, f.Type, rules)
+		_, required := rules["required"]
+
+		if name := tagName(f, "uri"); name != "" {
+			declared["path:"+name] = true
+			op.Parameters = append(op.Parameters, &OpenAPIParameter{Name: name, In: "path", Required: true, Schema: schema})
+			return
+		}
+		if name := tagName(f, "header"); name != "" {
+			op.Parameters = append(op.Parameters, &OpenAPIParameter{Name: name, In: "header", Required: required, Schema: schema})
+			return
+		}
+		if inQuery || (tagName(f, "form") != "" && tagName(f, "json") == "") {
+			name := tagName(f, "form")
+			if name == "" {
+				name = f.Name
+			}
+			if !inQuery {
+				body.Properties[name] = schema
+				if required {
+					body.Required = append(body.Required, name)
+				}
+				return
+			}
+			op.Parameters = append(op.Parameters, &OpenAPIParameter{Name: name, In: "query", Required: required, Schema: schema})
+			return
+		}
+		name := jsonName(f)
+		if name == "" {
+			return
+		}
+		body.Properties[name] = schema
+		if required {
+			body.Required = append(body.Required, name)
+		}
+	})
+	if len(body.Properties) > 0 && !inQuery {
+		op.RequestBody = &OpenAPIRequestBody{
+			Required: len(body.Required) > 0,
+			Content:  map[string]*OpenAPIMediaType{"application/json": {Schema: g.ref(st, body)}},
+		}
+	}
+}
+
+// describeMarker 将参数标记映射为 OpenAPI 参数，文件标记归入 multipart 请求体
+func (g *schemaGen) describeMarker(op *OpenAPIOperation, p ParamInfo, declared map[string]bool) {
+	if p.In == "file" {
+		g.addFileField(op, p.Name, p.Type)
+		return
+	}
+	declared[p.In+":"+p.Name] = true
+	op.Parameters = append(op.Parameters, &OpenAPIParameter{
+		Name:     p.Name,
+		In:       p.In,
+		Required: p.In == "path",
+		Schema:   g.schemaOf(p.Type),
+	})
+}
+
+func (g *schemaGen) addFileBody(op *OpenAPIOperation, t reflect.Type) {
+	name := "file"
+	if t.Kind() == reflect.Slice {
+		name = "files"
+	}
+	g.addFileField(op, name, t)
+}
+
+func (g *schemaGen) addFileField(op *OpenAPIOperation, name string, t reflect.Type) {
+	schema := &Schema{Type: "string", Format: "binary"}
+	if t.Kind() == reflect.Slice {
+		schema = &Schema{Type: "array", Items: schema}
+	}
+	if op.RequestBody == nil {
+		op.RequestBody = &OpenAPIRequestBody{Required: true, Content: make(map[string]*OpenAPIMediaType)}
+	}
+	mt := op.RequestBody.Content["multipart/form-data"]
+	if mt == nil {
+		mt = &OpenAPIMediaType{Schema: &Schema{Type: "object", Properties: make(map[string]*Schema)}}
+		op.RequestBody.Content["multipart/form-data"] = mt
+	}
+	mt.Schema.Properties[name] = schema
+	mt.Schema.Required = append(mt.Schema.Required, name)
+}
+
+// schemaOf 将 Go 类型转换为 Schema，具名结构体放入 components 并返回 $ref
+func (g *schemaGen) schemaOf(t reflect.Type) *Schema {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch {
+	case t == timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case t.Kind() != reflect.Struct && t.Implements(marshalerType),
+		t.Kind() == reflect.Struct && reflect.PointerTo(t).Implements(marshalerType):
+		return &Schema{} // 自定义序列化的类型 (如 gorm.DeletedAt)，无法推断结构
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int64, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: g.schemaOf(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: g.schemaOf(t.Elem())}
+	case reflect.Struct:
+		return g.ref(t, nil)
+	}
+	return &Schema{}
+}
+
+// ref 注册具名结构体；body 不为空时使用调用方已生成的结构 (请求 DTO)
+func (g *schemaGen) ref(t reflect.Type, body *Schema) *Schema {
+	if t.Name() == "" {
+		if body != nil {
+			return body
+		}
+		return g.object(t)
+	}
+	name, ok := g.names[t]
+	if !ok {
+		name = g.uniqueName(t)
+		g.names[t] = name
+		g.schemas[name] = &Schema{Type: "object"} // 先占位，支持自引用
+		if body == nil {
+			body = g.object(t)
+		}
+		g.schemas[name] = body
+	}
+	return &Schema{Ref: "#/components/schemas/" + name}
+}
+
+func (g *schemaGen) uniqueName(t reflect.Type) string {
+	name := t.Name()
+	if _, taken := g.schemas[name]; taken {
+		name = path.Base(t.PkgPath()) + "." + name
+	}
+	return name
+}
+
+// object 按 encoding/json 的规则展开结构体字段 (含匿名嵌入)
+func (g *schemaGen) object(t reflect.Type) *Schema {
+	s := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	walkFields(t, func(f reflect.StructField) {
+		name := jsonName(f)
+		if name == "" || f.Tag.Get("serialize") == "-" {
+			return
+		}
+		rules := parseRules(f)
+		fs := g.schemaOf(f.Type)
+		applyRules(fs, f.Type, rules)
+		s.Properties[name] = fs
+		if _, ok := rules["required"]; ok {
+			s.Required = append(s.Required, name)
+		}
+	})
+	sort.Strings(s.Required)
+	return s
+}
+
+// walkFields 遍历导出字段，未打 json tag 的匿名结构体字段会被展开
+func walkFields(t reflect.Type, fn func(f reflect.StructField)) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		ft := f.Type
+		if ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		if f.Anonymous && ft.Kind() == reflect.Struct && f.Tag.Get("json") == "" {
+			walkFields(ft, fn)
+			continue
+		}
+		if f.IsExported() {
+			fn(f)
+		}
+	}
+}
+
+func tagName(f reflect.StructField, key string) string {
+	name, _, _ := strings.Cut(f.Tag.Get(key), ",")
+	if name == "-" {
+		return ""
+	}
+	return name
+}
+
+// jsonName 字段在 JSON 中的名称，json:"-" 返回空
+func jsonName(f reflect.StructField) string {
+	tag := f.Tag.Get("json")
+	if tag == "-" {
+		return ""
+	}
+	if name, _, _ := strings.Cut(tag, ","); name != "" {
+		return name
+	}
+	return f.Name
+}
+
+// parseRules 合并 binding 与 validate 两种 tag 中的校验规则
+func parseRules(f reflect.StructField) map[string]*string {
+	rules := make(map[string]*string)
+	for _, tag := range []string{f.Tag.Get("binding"), f.Tag.Get("validate")} {
+		for _, rule := range strings.Split(tag, ",") {
+			if rule == "" {
+				continue
+			}
+			name, param, hasParam := strings.Cut(rule, "=")
+			if hasParam {
+				p := param
+				rules[name] = &p
+			} else {
+				rules[name] = nil
+			}
+		}
+	}
+	return rules
+}
+
+// applyRules 将 validator 规则映射到 Schema 约束
+func applyRules(s *Schema, t reflect.Type, rules map[string]*string) {
+	if s.Ref != "" {
+		return
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	num := func(p *string) *float64 {
+		if p == nil {
+			return nil
+		}
+		v, err := strconv.ParseFloat(*p, 64)
+		if err != nil {
+			return nil
+		}
+		return &v
+	}
+	length := func(p *string) *int {
+		if v := num(p); v != nil {
+			n := int(*v)
+			return &n
+		}
+		return nil
+	}
+	for name, p := range rules {
+		switch name {
+		case "email":
+			s.Format = "email"
+		case "url", "uri":
+			s.Format = "uri"
+		case "uuid", "uuid4":
+			s.Format = "uuid"
+		case "datetime":
+			s.Format = "date-time"
+		case "ip":
+			s.Format = "ipv4"
+		case "oneof":
+			if p != nil {
+				for _, v := range strings.Fields(*p) {
+					s.Enum = append(s.Enum, v)
+				}
+			}
+		case "min", "max", "len":
+			switch t.Kind() {
+			case reflect.String:
+				if name != "max" {
+					s.MinLength = length(p)
+				}
+				if name != "min" {
+					s.MaxLength = length(p)
+				}
+			case reflect.Slice, reflect.Array, reflect.Map:
+				if name != "max" {
+					s.MinItems = length(p)
+				}
+				if name != "min" {
+					s.MaxItems = length(p)
+				}
+			default:
+				if name != "max" {
+					s.Minimum = num(p)
+				}
+				if name != "min" {
+					s.Maximum = num(p)
+				}
+			}
+		case "gte", "lte":
+			// 与 min / max 相同：字符串校验长度，集合校验元素个数
+			switch t.Kind() {
+			case reflect.String:
+				if name == "gte" {
+					s.MinLength = length(p)
+				} else {
+					s.MaxLength = length(p)
+				}
+			case reflect.Slice, reflect.Array, reflect.Map:
+				if name == "gte" {
+					s.MinItems = length(p)
+				} else {
+					s.MaxItems = length(p)
+				}
+			default:
+				if name == "gte" {
+					s.Minimum = num(p)
+				} else {
+					s.Maximum = num(p)
+				}
+			}
+		case "gt":
+			s.ExclusiveMinimum = num(p)
+		case "lt":
+			s.ExclusiveMaximum = num(p)
+		}
+	}
+}
+
+// operationID 由方法与路径生成唯一 ID：POST /auth/refresh-token -> postAuthRefreshToken
+func operationID(method, p string) string {
+	var b strings.Builder
+	b.WriteString(strings.ToLower(method))
+	upper := true
+	for _, r := range p {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,99 @@
+package gnest_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"blog/internal/infra/gnest"
+)
+
+type lengthDTO struct {
+	Name string `json:"name" binding:"gte=2,lte=20"`
+	Age  int    `json:"age" binding:"gte=1,lte=150"`
+}
+
+func TestOpenAPIStringBoundsAreLengths(t *testing.T) {
+	app := gnest.New()
+	app.POST("/people", func(dto *lengthDTO) string { return dto.Name })
+	doc := app.OpenAPI(gnest.OpenAPIConfig{Title: "test"})
+
+	schema := doc.Components.Schemas["lengthDTO"]
+	if schema == nil {
+		t.Fatalf("lengthDTO schema missing: %v", doc.Components.Schemas)
+	}
+	name, age := schema.Properties["name"], schema.Properties["age"]
+	if name.MinLength == nil || *name.MinLength != 2 || name.MaxLength == nil || *name.MaxLength != 20 || name.Minimum != nil {
+		t.Errorf("name: expected minLength 2 / maxLength 20, got %+v", name)
+	}
+	if age.Minimum == nil || *age.Minimum != 1 || age.Maximum == nil || *age.Maximum != 150 {
+		t.Errorf("age: expected minimum 1 / maximum 150, got %+v", age)
+	}
+}
+
+func TestSwaggerUIAssetsURL(t *testing.T) {
+	app := gnest.NewTestingApp()
+	app.UseOpenAPI(gnest.OpenAPIConfig{Title: "test", AssetsURL: "/static/swagger-ui/"})
+	if err := app.Compile(); err != nil {
+		t.Fatal(err)
+	}
+	body := app.Client(t).GET("/docs").ExpectStatus(http.StatusOK).Response().Body.String()
+	if !strings.Contains(body, `src="/static/swagger-ui/swagger-ui-bundle.js"`) {
+		t.Errorf("expected local Swagger UI assets, got %s", body)
+	}
+}
+
+type userV1 struct {
+	Name string `json:"name"`
+}
+
+type userV2 struct {
+	FirstName string `json:"firstName"`
+	LastName  string `json:"lastName"`
+}
+
+func TestOpenAPIDocumentsEachVersion(t *testing.T) {
+	app := gnest.NewTestingApp()
+	app.EnableVersioning(gnest.HeaderVersioning("X-API-Version"), gnest.VersioningOptions{DefaultVersion: "1"})
+	app.GET("/users/me", func() userV1 { return userV1{} }, gnest.Version("1"))
+	app.GET("/users/me", func() userV2 { return userV2{} }, gnest.Version("2"))
+	app.GET("/ping", func() string { return "pong" }, gnest.Version(gnest.VersionNeutral))
+	app.UseOpenAPI(gnest.OpenAPIConfig{Title: "test"})
+	if err := app.Compile(); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct{ version, schema string }{{"", "userV1"}, {"1", "userV1"}, {"2", "userV2"}} {
+		doc := app.OpenAPI(gnest.OpenAPIConfig{Title: "test", APIVersion: tc.version})
+		op := doc.Paths["/users/me"]["get"]
+		if op == nil || doc.Paths["/ping"]["get"] == nil {
+			t.Fatalf("version %q: expected /users/me and the neutral /ping, got %v", tc.version, doc.Paths)
+		}
+		if ref := op.Responses["200"].Content["application/json"].Schema.Ref; !strings.HasSuffix(ref, "/"+tc.schema) {
+			t.Errorf("version %q: expected %s, got %s", tc.version, tc.schema, ref)
+		}
+		var header *gnest.OpenAPIParameter
+		for _, p := range op.Parameters {
+			if p.In == "header" && p.Name == "X-API-Version" {
+				header = p
+			}
+		}
+		want := tc.version
+		if want == "" {
+			want = "1"
+		}
+		if header == nil || len(header.Schema.Enum) != 1 || header.Schema.Enum[0] != want || header.Required != (want != "1") {
+			t.Errorf("version %q: expected the X-API-Version header to be documented, got %+v", tc.version, header)
+		}
+	}
+
+	var spec gnest.OpenAPIDocument
+	app.Client(t).GET("/openapi.json").Query("version", "2").ExpectStatus(http.StatusOK).DecodeInto(&spec)
+	if ref := spec.Paths["/users/me"]["get"].Responses["200"].Content["application/json"].Schema.Ref; !strings.HasSuffix(ref, "/userV2") {
+		t.Errorf("expected ?version=2 to select the v2 operation, got %s", ref)
+	}
+	body := app.Client(t).GET("/docs").ExpectStatus(http.StatusOK).Response().Body.String()
+	if !strings.Contains(body, `/openapi.json?version=1`) || !strings.Contains(body, `/openapi.json?version=2`) {
+		t.Errorf("expected Swagger UI to list both versions, got %s", body)
+	}
+}
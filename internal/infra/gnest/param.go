@@ -0,0 +1,266 @@
+package gnest
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ==========================================
+// 参数标记 (Parameter Decorators)
+// ==========================================
+
+// 参数标记作为方法级增强器传入，按声明顺序依次绑定到处理函数中类型相同的参数上：
+//
+//	rg.GET("/:id", ctrl.Detail, gnest.Path[int]{"id"}, gnest.Query[string]{"lang"})
+//	func (ctrl *PostController) Detail(id int, lang string) (*post.Post, error)
+//
+// 标记在路由注册时解析，类型转换失败返回带参数名的 400
+
+// Path 路径参数，对应 @Param('id')
+type Path[T any] struct{ Name string }
+
+// Query 查询参数，对应 @Query('page')；T 为切片时读取同名的多个值，缺省时为零值
+type Query[T any] struct{ Name string }
+
+// Header 请求头，对应 @Headers('X-Request-Id')
+type Header[T any] struct{ Name string }
+
+// Cookie Cookie 值，缺省时为零值
+type Cookie[T any] struct{ Name string }
+
+// UploadedFile 指定表单字段的单个文件，绑定到 *multipart.FileHeader 参数
+type UploadedFile struct{ Name string }
+
+// UploadedFiles 指定表单字段的多个文件，绑定到 []*multipart.FileHeader 参数
+type UploadedFiles struct{ Name string }
+
+// ParamInfo 是参数标记在路由表中的描述，供 OpenAPI 文档使用
+type ParamInfo struct {
+	In   string // path / query / header / cookie / file
+	Name string
+	Type reflect.Type
+}
+
+// paramMarker 由所有参数标记实现
+type paramMarker interface {
+	paramInfo() ParamInfo
+}
+
+func (m Path[T]) paramInfo() ParamInfo { return ParamInfo{In: "path", Name: m.Name, Type: typeOf[T]()} }
+func (m Query[T]) paramInfo() ParamInfo {
+	return ParamInfo{In: "query", Name: m.Name, Type: typeOf[T]()}
+}
+func (m Header[T]) paramInfo() ParamInfo {
+	return ParamInfo{In: "header", Name: m.Name, Type: typeOf[T]()}
+}
+func (m Cookie[T]) paramInfo() ParamInfo {
+	return ParamInfo{In: "cookie", Name: m.Name, Type: typeOf[T]()}
+}
+func (m UploadedFile) paramInfo() ParamInfo {
+	return ParamInfo{In: "file", Name: m.Name, Type: fileHeaderType}
+}
+func (m UploadedFiles) paramInfo() ParamInfo {
+	return ParamInfo{In: "file", Name: m.Name, Type: reflect.SliceOf(fileHeaderType)}
+}
+
+func typeOf[T any]() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
+
+// bindParamMarkers 将标记按顺序分配给处理函数的参数，返回与参数一一对应的描述 (非标记参数为 nil)；
+// 标记与参数不匹配或类型不支持时返回错误，由 Handle 记入注册阶段的错误
+func bindParamMarkers(hTyp reflect.Type, markers []ParamInfo) ([]*ParamInfo, error) {
+	params := make([]*ParamInfo, hTyp.NumIn())
+	next := 0
+	for i := 0; i < hTyp.NumIn() && next < len(markers); i++ {
+		if hTyp.In(i) == markers[next].Type {
+			m := markers[next]
+			params[i] = &m
+			next++
+		}
+	}
+	if next < len(markers) {
+		m := markers[next]
+		return nil, fmt.Errorf("%s parameter %q (%v) does not match any remaining parameter of handler %v", m.In, m.Name, m.Type, hTyp)
+	}
+	for _, m := range markers {
+		if m.In != "file" && !convertible(m.Type, m.In != "path") {
+			return nil, fmt.Errorf("%s parameter %q: unsupported type %v", m.In, m.Name, m.Type)
+		}
+	}
+	return params, nil
+}
+
+// makeMarkerResolver 在注册阶段生成参数标记的解析器
+func makeMarkerResolver(p ParamInfo) argumentResolver {
+	if p.In == "file" {
+		if p.Type.Kind() == reflect.Slice {
+			return func(c *gin.Context) (reflect.Value, error) {
+				form, err := c.MultipartForm()
+				if err != nil {
+					return reflect.Value{}, paramError(p, err)
+				}
+				return reflect.ValueOf(form.File[p.Name]), nil
+			}
+		}
+		return func(c *gin.Context) (reflect.Value, error) {
+			f, err := c.FormFile(p.Name)
+			if err != nil {
+				return reflect.Value{}, paramError(p, err)
+			}
+			return reflect.ValueOf(f), nil
+		}
+	}
+
+	read := func(c *gin.Context) ([]string, bool) {
+		switch p.In {
+		case "path":
+			v, ok := c.Params.Get(p.Name)
+			return []string{v}, ok
+		case "query":
+			return c.GetQueryArray(p.Name)
+		case "header":
+			vs := c.Request.Header.Values(p.Name)
+			return vs, len(vs) > 0
+		case "cookie":
+			v, err := c.Cookie(p.Name)
+			return []string{v}, err == nil
+		}
+		return nil, false
+	}
+	return func(c *gin.Context) (reflect.Value, error) {
+		raw, ok := read(c)
+		if !ok {
+			if p.In == "path" {
+				return reflect.Value{}, paramError(p, fmt.Errorf("missing"))
+			}
+			return reflect.Zero(p.Type), nil
+		}
+		v, err := convertStrings(raw, p.Type)
+		if err != nil {
+			return reflect.Value{}, paramError(p, err)
+		}
+		return v, nil
+	}
+}
+
+// paramError 生成带参数名的 400 异常
+func paramError(p ParamInfo, err error) *HttpException {
+	in := p.In
+	if in == "file" {
+		in = "form file"
+	}
+	return BadRequest(fmt.Sprintf("invalid %s parameter %q", in, p.Name)).
+		WithDetails([]FieldError{{Field: p.Name, Rule: "type", Param: p.Type.String(), Message: err.Error()}}).
+		WithCause(err)
+}
+
+var (
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	durationType        = reflect.TypeOf(time.Duration(0))
+)
+
+// convertible 判断类型能否由字符串转换得到，multi 表示允许切片 (查询参数 / 请求头可能有多个值)
+func convertible(t reflect.Type, multi bool) bool {
+	if multi && t.Kind() == reflect.Slice && t.Elem().Kind() != reflect.Uint8 {
+		return convertible(t.Elem(), false)
+	}
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == durationType || reflect.PointerTo(t).Implements(textUnmarshalerType) {
+		return true
+	}
+	switch t.Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// convertStrings 将原始字符串转换为目标类型，切片按元素逐个转换
+func convertStrings(raw []string, t reflect.Type) (reflect.Value, error) {
+	if t.Kind() == reflect.Slice && t.Elem().Kind() != reflect.Uint8 {
+		out := reflect.MakeSlice(t, 0, len(raw))
+		for _, s := range raw {
+			v, err := convertString(s, t.Elem())
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			out = reflect.Append(out, v)
+		}
+		return out, nil
+	}
+	var s string
+	if len(raw) > 0 {
+		s = raw[0]
+	}
+	return convertString(s, t)
+}
+
+func convertString(s string, t reflect.Type) (reflect.Value, error) {
+	if t.Kind() == reflect.Ptr {
+		v, err := convertString(s, t.Elem())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		p := reflect.New(t.Elem())
+		p.Elem().Set(v)
+		return p, nil
+	}
+	if reflect.PointerTo(t).Implements(textUnmarshalerType) {
+		p := reflect.New(t)
+		if err := p.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s)); err != nil {
+			return reflect.Value{}, err
+		}
+		return p.Elem(), nil
+	}
+	v := reflect.New(t).Elem()
+	if t == durationType {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		v.SetInt(int64(d))
+		return v, nil
+	}
+	switch t.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, t.Bits())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, t.Bits())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, t.Bits())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		v.SetFloat(f)
+	default:
+		return reflect.Value{}, fmt.Errorf("unsupported parameter type %v", t)
+	}
+	return v, nil
+}
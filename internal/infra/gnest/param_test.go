@@ -0,0 +1,48 @@
+package gnest_test
+
+import (
+	"strings"
+	"testing"
+
+	"blog/internal/infra/gnest"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestParamMarkerMismatchIsRegistrationError(t *testing.T) {
+	app := gnest.NewTestingApp()
+	app.GET("/items/:id", func(id string) string { return id }, gnest.Path[int]{"id"})
+	err := app.Compile()
+	if err == nil || !strings.Contains(err.Error(), `path parameter "id"`) {
+		t.Fatalf("expected a registration error for the mismatched marker, got %v", err)
+	}
+}
+
+func TestParamMarkers(t *testing.T) {
+	app := gnest.NewTestingApp()
+	app.GET("/items/:id", func(id int, lang string) interface{} {
+		return map[string]interface{}{"id": id, "lang": lang}
+	}, gnest.Path[int]{"id"}, gnest.Query[string]{"lang"})
+	if err := app.Compile(); err != nil {
+		t.Fatal(err)
+	}
+	var got struct {
+		ID   int    `json:"id"`
+		Lang string `json:"lang"`
+	}
+	app.Client(t).GET("/items/7").Query("lang", "zh").ExpectStatus(200).DecodeInto(&got)
+	if got.ID != 7 || got.Lang != "zh" {
+		t.Errorf("unexpected binding: %+v", got)
+	}
+	app.Client(t).GET("/items/x").ExpectStatus(400)
+}
+
+func TestUnsupportedEnhancerIsRegistrationError(t *testing.T) {
+	app := gnest.NewTestingApp()
+	app.GET("/middleware", func() string { return "ok" }, gin.HandlerFunc(func(c *gin.Context) {}))
+	app.GET("/unknown", func() string { return "ok" }, 42)
+	err := app.Compile()
+	if err == nil || !strings.Contains(err.Error(), "gin middleware") || !strings.Contains(err.Error(), "unsupported route enhancer int") {
+		t.Fatalf("expected registration errors for both enhancers, got %v", err)
+	}
+}
@@ -0,0 +1,412 @@
+package gnest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// ==========================================
+// 定时任务 (Task Scheduling)
+// ==========================================
+
+// Scheduled 由需要定时任务的 Provider 实现，调度器在 OnApplicationBootstrap 时调用 Schedules 登记任务：
+//
+//	func (s *PostService) Schedules(r *gnest.SchedulerRegistry) {
+//		r.Cron("publish-scheduled-posts", "0 * * * * *", s.PublishScheduled, gnest.InTimeZone("Asia/Shanghai"))
+//		r.Interval("flush-view-counters", 30*time.Second, s.FlushViews)
+//	}
+type Scheduled interface {
+	Schedules(r *SchedulerRegistry)
+}
+
+// JobFunc 任务函数，ctx 在应用关闭时取消
+type JobFunc func(ctx context.Context) error
+
+// ScheduleLocker 分布式锁钩子：多实例部署时只有拿到锁的实例执行任务。
+// 获取失败返回 ok=false，本次执行被跳过；unlock 在任务结束后调用
+type ScheduleLocker interface {
+	TryLock(ctx context.Context, key string, ttl time.Duration) (unlock func(), ok bool, err error)
+}
+
+// ScheduleOptions 调度器配置
+type ScheduleOptions struct {
+	Location *time.Location // cron 表达式默认时区，默认 time.Local
+	Locker   ScheduleLocker // 可选的分布式锁
+	LockTTL  time.Duration  // 锁的最长持有时间，默认 1 分钟
+}
+
+// ScheduleModule 创建全局的调度模块，导出 *SchedulerRegistry：
+//
+//	Imports: []*gnest.Module{gnest.ScheduleModule(gnest.ScheduleOptions{Locker: redisClient})}
+func ScheduleModule(opts ScheduleOptions) *Module {
+	return &Module{
+		Name:      "ScheduleModule",
+		Global:    true,
+		Providers: []interface{}{NewSchedulerRegistry(opts)},
+		Exports:   []interface{}{(*SchedulerRegistry)(nil)},
+	}
+}
+
+// 任务类型
+const (
+	JobCron     = "cron"
+	JobInterval = "interval"
+	JobTimeout  = "timeout"
+)
+
+// JobInfo 任务的运行状态，供管理接口展示
+type JobInfo struct {
+	Name         string        `json:"name"`
+	Kind         string        `json:"kind"`
+	Spec         string        `json:"spec"`
+	Running      bool          `json:"running"`
+	Runs         int64         `json:"runs"`
+	Skipped      int64         `json:"skipped"` // 因上次未结束或未拿到锁而跳过的次数
+	LastRun      time.Time     `json:"lastRun,omitempty"`
+	LastDuration time.Duration `json:"lastDuration"`
+	LastError    string        `json:"lastError,omitempty"`
+	NextRun      time.Time     `json:"nextRun,omitempty"`
+}
+
+// JobOption 任务选项
+type JobOption func(j *job)
+
+// InTimeZone 指定 cron 表达式的时区，如 "Asia/Shanghai"
+func InTimeZone(name string) JobOption {
+	return func(j *job) { j.tz = name }
+}
+
+// AllowOverlap 允许上一次执行未结束时再次执行，默认跳过
+func AllowOverlap() JobOption {
+	return func(j *job) { j.overlap = true }
+}
+
+// WithoutLock 即使配置了分布式锁也在每个实例上执行 (如清理本地缓存)
+func WithoutLock() JobOption {
+	return func(j *job) { j.noLock = true }
+}
+
+// LockTTL 覆盖该任务的锁持有时间，应大于任务的最长执行时间
+func LockTTL(d time.Duration) JobOption {
+	return func(j *job) { j.lockTTL = d }
+}
+
+// cronParser 秒字段可选，兼容标准 5 段表达式
+var cronParser = cron.NewParser(cron.SecondOptional | cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
+
+type job struct {
+	name    string
+	kind    string
+	spec    string
+	fn      JobFunc
+	tz      string
+	overlap bool
+	noLock  bool
+	lockTTL time.Duration
+
+	entry   cron.EntryID
+	timer   *time.Timer
+	running atomic.Int32
+
+	mu      sync.Mutex
+	runs    int64
+	skipped int64
+	lastRun time.Time
+	lastDur time.Duration
+	lastErr error
+}
+
+// SchedulerRegistry 任务注册表：登记、列出、删除与手动触发任务，对应 Nest 的 SchedulerRegistry
+type SchedulerRegistry struct {
+	opts    ScheduleOptions
+	cron    *cron.Cron
+	ctx     context.Context
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+	mu      sync.Mutex
+	jobs    map[string]*job
+	started bool
+	stopped bool    // OnModuleDestroy 已开始，拒绝新的执行；与 wg.Add 同在 mu 内读写
+	errs    []error // 启动前登记失败的任务，OnApplicationBootstrap 时返回
+	app     *GnestApp
+}
+
+// NewSchedulerRegistry 创建注册表，一般通过 ScheduleModule 注册
+func NewSchedulerRegistry(opts ScheduleOptions) *SchedulerRegistry {
+	if opts.Location == nil {
+		opts.Location = time.Local
+	}
+	if opts.LockTTL <= 0 {
+		opts.LockTTL = time.Minute
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &SchedulerRegistry{
+		opts:   opts,
+		cron:   cron.New(cron.WithParser(cronParser), cron.WithLocation(opts.Location)),
+		ctx:    ctx,
+		cancel: cancel,
+		jobs:   make(map[string]*job),
+	}
+}
+
+func (r *SchedulerRegistry) setApp(app *GnestApp) { r.app = app }
+
+// Cron 按 cron 表达式执行，支持 5 段或带秒的 6 段以及 @every / @daily 等描述符
+func (r *SchedulerRegistry) Cron(name, spec string, fn JobFunc, opts ...JobOption) error {
+	return r.add(&job{name: name, kind: JobCron, spec: spec, fn: fn}, opts)
+}
+
+// Interval 以固定间隔执行 (精度为秒)
+func (r *SchedulerRegistry) Interval(name string, every time.Duration, fn JobFunc, opts ...JobOption) error {
+	return r.add(&job{name: name, kind: JobInterval, spec: every.String(), fn: fn}, opts)
+}
+
+// Timeout 在调度器启动 (或登记时已启动) 后延迟执行一次
+func (r *SchedulerRegistry) Timeout(name string, after time.Duration, fn JobFunc, opts ...JobOption) error {
+	return r.add(&job{name: name, kind: JobTimeout, spec: after.String(), fn: fn}, opts)
+}
+
+func (r *SchedulerRegistry) add(j *job, opts []JobOption) error {
+	for _, o := range opts {
+		o(j)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	err := r.validate(j)
+	if err == nil && r.started {
+		err = r.schedule(j)
+	}
+	if err != nil {
+		err = fmt.Errorf("schedule job %q: %w", j.name, err)
+		if !r.started {
+			r.errs = append(r.errs, err)
+		}
+		return err
+	}
+	r.jobs[j.name] = j
+	return nil
+}
+
+func (r *SchedulerRegistry) validate(j *job) error {
+	if j.fn == nil {
+		return errors.New("nil job function")
+	}
+	if _, dup := r.jobs[j.name]; dup {
+		return errors.New("duplicate job name")
+	}
+	switch j.kind {
+	case JobCron:
+		if j.tz != "" {
+			if _, err := time.LoadLocation(j.tz); err != nil {
+				return err
+			}
+			j.spec = "CRON_TZ=" + j.tz + " " + j.spec
+		}
+		if _, err := cronParser.Parse(j.spec); err != nil {
+			return err
+		}
+	case JobInterval:
+		if d, _ := time.ParseDuration(j.spec); d < time.Second {
+			return errors.New("interval must be at least 1s")
+		}
+	}
+	return nil
+}
+
+// schedule 将任务挂到 cron / 定时器上，调用方持有 r.mu
+func (r *SchedulerRegistry) schedule(j *job) error {
+	run := func() { r.run(r.ctx, j) }
+	switch j.kind {
+	case JobCron:
+		id, err := r.cron.AddFunc(j.spec, run)
+		if err != nil {
+			return err
+		}
+		j.entry = id
+	case JobInterval:
+		d, _ := time.ParseDuration(j.spec)
+		j.entry = r.cron.Schedule(cron.Every(d), cron.FuncJob(run))
+	case JobTimeout:
+		d, _ := time.ParseDuration(j.spec)
+		j.timer = time.AfterFunc(d, func() {
+			run()
+			r.Remove(j.name)
+		})
+	}
+	return nil
+}
+
+// run 执行一次任务：跳过重叠执行，按需获取分布式锁，捕获 panic 并记录结果
+func (r *SchedulerRegistry) run(ctx context.Context, j *job) error {
+	if !j.overlap && !j.running.CompareAndSwap(0, 1) {
+		j.mu.Lock()
+		j.skipped++
+		j.mu.Unlock()
+		return Conflict(fmt.Sprintf("job %q is still running", j.name)).WithCode("JOB_RUNNING")
+	}
+	if j.overlap {
+		j.running.Add(1)
+	}
+	defer j.running.Add(-1)
+	// 关闭开始后不再接受新的执行，保证 wg.Add 不会与 OnModuleDestroy 中的 Wait 并发
+	r.mu.Lock()
+	if r.stopped {
+		r.mu.Unlock()
+		return Conflict(fmt.Sprintf("job %q: scheduler is stopped", j.name)).WithCode("SCHEDULER_STOPPED")
+	}
+	r.wg.Add(1)
+	r.mu.Unlock()
+	defer r.wg.Done()
+
+	if r.opts.Locker != nil && !j.noLock {
+		ttl := j.lockTTL
+		if ttl <= 0 {
+			ttl = r.opts.LockTTL
+		}
+		unlock, ok, err := r.opts.Locker.TryLock(ctx, "gnest:schedule:"+j.name, ttl)
+		if err != nil || !ok {
+			j.mu.Lock()
+			j.skipped++
+			j.mu.Unlock()
+			if err != nil {
+				log.Printf("[Gnest] schedule job %q: acquire lock: %v", j.name, err)
+				return err
+			}
+			return Conflict(fmt.Sprintf("job %q is running on another instance", j.name)).WithCode("JOB_LOCKED")
+		}
+		defer unlock()
+	}
+
+	start := time.Now()
+	err := func() (err error) {
+		defer func() {
+			if rec := recover(); rec != nil {
+				err = fmt.Errorf("panic: %v", rec)
+			}
+		}()
+		return j.fn(ctx)
+	}()
+	j.mu.Lock()
+	j.runs++
+	j.lastRun, j.lastDur, j.lastErr = start, time.Since(start), err
+	j.mu.Unlock()
+	if err != nil {
+		log.Printf("[Gnest] schedule job %q failed: %v", j.name, err)
+	}
+	return err
+}
+
+// Trigger 立即执行一次任务并返回其错误，同样遵循防重叠与分布式锁
+func (r *SchedulerRegistry) Trigger(ctx context.Context, name string) error {
+	r.mu.Lock()
+	j, ok := r.jobs[name]
+	r.mu.Unlock()
+	if !ok {
+		return NotFound(fmt.Sprintf("job %q not found", name))
+	}
+	return r.run(ctx, j)
+}
+
+// Remove 删除任务，正在执行的实例不受影响
+func (r *SchedulerRegistry) Remove(name string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	j, ok := r.jobs[name]
+	if !ok {
+		return false
+	}
+	if j.entry != 0 {
+		r.cron.Remove(j.entry)
+	}
+	if j.timer != nil {
+		j.timer.Stop()
+	}
+	delete(r.jobs, name)
+	return true
+}
+
+// Jobs 按名称列出所有任务的状态
+func (r *SchedulerRegistry) Jobs() []JobInfo {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make([]JobInfo, 0, len(r.jobs))
+	for _, j := range r.jobs {
+		j.mu.Lock()
+		info := JobInfo{
+			Name: j.name, Kind: j.kind, Spec: j.spec,
+			Running: j.running.Load() > 0,
+			Runs:    j.runs, Skipped: j.skipped,
+			LastRun: j.lastRun, LastDuration: j.lastDur,
+		}
+		if j.lastErr != nil {
+			info.LastError = j.lastErr.Error()
+		}
+		j.mu.Unlock()
+		if j.entry != 0 {
+			info.NextRun = r.cron.Entry(j.entry).Next
+		}
+		out = append(out, info)
+	}
+	sort.Slice(out, func(a, b int) bool { return out[a].Name < out[b].Name })
+	return out
+}
+
+// OnApplicationBootstrap 收集容器中 Scheduled Provider 的任务并启动调度，登记失败时中止启动
+func (r *SchedulerRegistry) OnApplicationBootstrap(ctx context.Context) error {
+	if r.app != nil {
+		for _, inst := range r.app.container {
+			if s, ok := inst.(Scheduled); ok {
+				s.Schedules(r)
+			}
+		}
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.errs) > 0 {
+		return errors.Join(r.errs...)
+	}
+	for _, j := range r.jobs {
+		if err := r.schedule(j); err != nil {
+			return fmt.Errorf("schedule job %q: %w", j.name, err)
+		}
+	}
+	r.started = true
+	r.cron.Start()
+	return nil
+}
+
+// OnModuleDestroy 停止调度并拒绝新的执行，随即取消任务的 ctx 通知正在执行的任务退出，
+// 再等待它们结束，直到钩子超时
+func (r *SchedulerRegistry) OnModuleDestroy(ctx context.Context) error {
+	r.mu.Lock()
+	r.started = false
+	r.stopped = true
+	for _, j := range r.jobs {
+		if j.timer != nil {
+			j.timer.Stop()
+		}
+	}
+	r.mu.Unlock()
+	r.cron.Stop()
+	r.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		r.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("waiting for running jobs: %w", ctx.Err())
+	}
+}
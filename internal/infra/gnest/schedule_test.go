@@ -0,0 +1,56 @@
+package gnest_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"blog/internal/infra/gnest"
+)
+
+func TestSchedulerDestroyCancelsRunningJobs(t *testing.T) {
+	r := gnest.NewSchedulerRegistry(gnest.ScheduleOptions{})
+	started := make(chan struct{})
+	err := r.Timeout("wait-for-shutdown", time.Millisecond, func(ctx context.Context) error {
+		close(started)
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.OnApplicationBootstrap(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := r.OnModuleDestroy(ctx); err != nil {
+		t.Fatalf("expected the running job to observe cancellation, got %v", err)
+	}
+}
+
+func TestSchedulerRefusesRunsAfterDestroy(t *testing.T) {
+	r := gnest.NewSchedulerRegistry(gnest.ScheduleOptions{})
+	ran := false
+	if err := r.Interval("tick", time.Hour, func(context.Context) error { ran = true; return nil }); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.OnApplicationBootstrap(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.OnModuleDestroy(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	err := r.Trigger(context.Background(), "tick")
+	var he *gnest.HttpException
+	if !errors.As(err, &he) || he.Code != "SCHEDULER_STOPPED" {
+		t.Fatalf("expected SCHEDULER_STOPPED, got %v", err)
+	}
+	if ran {
+		t.Error("job ran after the scheduler stopped")
+	}
+}
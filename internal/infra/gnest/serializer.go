@@ -0,0 +1,395 @@
+package gnest
+
+import (
+	"encoding"
+	"encoding/json"
+	"encoding/xml"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// ==========================================
+// 响应序列化 (Serialization Groups)
+// ==========================================
+
+// 注册为全局拦截器后，返回值中的结构体按 serialize 标签过滤字段，模型可直接作为响应返回：
+//
+//	type User struct {
+//		Password  string    `json:"password" serialize:"-"`                   // 始终排除
+//		Email     string    `json:"email" serialize:"groups=admin,self"`      // 仅 admin / self 分组可见
+//		CreatedAt time.Time `json:"createdAt" serialize:"format=2006-01-02"`  // 按格式输出时间
+//	}
+//
+//	rg.UseMetadata(gnest.SerializeGroups("self")) // 路由 (组) 声明分组
+//
+// 生效的分组为路由声明的分组加上 SerializerOptions.Groups 按调用方 (如角色) 返回的分组。
+// 多个选项以 ";" 分隔，如 serialize:"groups=admin;format=2006-01-02"。
+// 模型实现 Exposer 可追加计算字段；过滤后的对象按 json 标签命名，XML 同样使用 json 名称
+
+// SerializeGroupsKey 路由序列化分组的元数据键
+const SerializeGroupsKey = "serializeGroups"
+
+// SerializeGroups 声明路由 (组) 生效的序列化分组
+func SerializeGroups(groups ...string) Metadata {
+	return SetMetadata(SerializeGroupsKey, groups)
+}
+
+// Exposer 由模型可选实现，返回追加在字段之后的计算字段，同名时覆盖原字段
+type Exposer interface {
+	Expose(groups []string) []ExposedField
+}
+
+// ExposedField 计算字段
+type ExposedField struct {
+	Name  string
+	Value interface{}
+}
+
+// SerializerOptions 序列化配置
+type SerializerOptions struct {
+	Groups func(c *gin.Context) []string // 按调用方追加分组，如 admin 角色返回 ["admin"]
+}
+
+// SerializerInterceptor 序列化拦截器，Options 从容器注入，未注入时只使用路由声明的分组
+type SerializerInterceptor struct {
+	Options *SerializerOptions
+}
+
+func (s *SerializerInterceptor) Intercept(c *gin.Context, next func() interface{}) interface{} {
+	res := next()
+	if c.Writer.Written() || !isPlainResult(res) {
+		return res
+	}
+	groups, _ := GetMetadata[[]string](c, SerializeGroupsKey)
+	if s.Options != nil && s.Options.Groups != nil {
+		groups = append(append([]string(nil), groups...), s.Options.Groups(c)...)
+	}
+	return Serialize(res, groups...)
+}
+
+// Serialize 按分组过滤 v 中的结构体字段，无需过滤的值原样返回；
+// 也可在拦截器之外使用，如 WebSocket 推送或消息发送前
+func Serialize(v interface{}, groups ...string) interface{} {
+	if v == nil {
+		return nil
+	}
+	return serializeValue(reflect.ValueOf(v), groups)
+}
+
+// SerializedObject 过滤后的结构体，按字段声明顺序输出
+type SerializedObject struct {
+	name   string // 源类型名，作为 XML 元素名
+	fields []orderedField
+}
+
+func (o SerializedObject) MarshalJSON() ([]byte, error) {
+	return marshalFieldsJSON(o.fields)
+}
+
+func (o SerializedObject) MarshalXML(enc *xml.Encoder, start xml.StartElement) error {
+	if start.Name.Local == "SerializedObject" {
+		start = xml.StartElement{Name: xml.Name{Local: o.name}}
+	}
+	return encodeFieldsXML(enc, start, o.fields)
+}
+
+func (o SerializedObject) EncodeMsgpack(enc *msgpack.Encoder) error {
+	return encodeFieldsMsgpack(enc, o.fields)
+}
+
+var (
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	exposerType       = reflect.TypeOf((*Exposer)(nil)).Elem()
+	interfaceType     = reflect.TypeOf((*interface{})(nil)).Elem()
+)
+
+func serializeValue(v reflect.Value, groups []string) interface{} {
+	switch v.Kind() {
+	case reflect.Invalid:
+		return nil
+	case reflect.Interface, reflect.Ptr:
+		if v.IsNil() {
+			return v.Interface()
+		}
+		if !needsSerialize(v.Type()) {
+			return v.Interface()
+		}
+		return serializeValue(v.Elem(), groups)
+	}
+	if env, ok := v.Interface().(Envelope); ok {
+		env.Data = Serialize(env.Data, groups...)
+		return env
+	}
+	if !needsSerialize(v.Type()) {
+		return v.Interface()
+	}
+
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return v.Interface()
+		}
+		out := make([]interface{}, v.Len())
+		for i := range out {
+			out[i] = serializeValue(v.Index(i), groups)
+		}
+		return out
+	case reflect.Map:
+		if v.IsNil() {
+			return v.Interface()
+		}
+		out := reflect.MakeMapWithSize(reflect.MapOf(v.Type().Key(), interfaceType), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			item := reflect.ValueOf(serializeValue(iter.Value(), groups))
+			if !item.IsValid() {
+				item = reflect.Zero(interfaceType)
+			}
+			out.SetMapIndex(iter.Key(), item)
+		}
+		return out.Interface()
+	case reflect.Struct:
+		return serializeStruct(v, groups)
+	}
+	return v.Interface()
+}
+
+func serializeStruct(v reflect.Value, groups []string) SerializedObject {
+	plan := structPlanOf(v.Type())
+	obj := SerializedObject{name: v.Type().Name(), fields: make([]orderedField, 0, len(plan.fields))}
+	if obj.name == "" {
+		obj.name = "object"
+	}
+	for _, f := range plan.fields {
+		if len(f.groups) > 0 && !hasAnyGroup(f.groups, groups) {
+			continue
+		}
+		fv, ok := fieldByIndex(v, f.index)
+		if !ok || (f.omitEmpty && isEmptyValue(fv)) {
+			continue
+		}
+		var value interface{}
+		if f.format != "" {
+			value = formatTime(fv, f.format)
+		} else {
+			value = serializeValue(fv, groups)
+		}
+		obj.fields = append(obj.fields, orderedField{f.name, value})
+	}
+
+	if exposer, ok := exposerOf(v); ok {
+		for _, ef := range exposer.Expose(groups) {
+			value := Serialize(ef.Value, groups...)
+			replaced := false
+			for i := range obj.fields {
+				if obj.fields[i].key == ef.Name {
+					obj.fields[i].value, replaced = value, true
+				}
+			}
+			if !replaced {
+				obj.fields = append(obj.fields, orderedField{ef.Name, value})
+			}
+		}
+	}
+	return obj
+}
+
+// exposerOf 值或其指针实现 Exposer 时返回
+func exposerOf(v reflect.Value) (Exposer, bool) {
+	if e, ok := v.Interface().(Exposer); ok {
+		return e, true
+	}
+	if v.CanAddr() {
+		e, ok := v.Addr().Interface().(Exposer)
+		return e, ok
+	}
+	if reflect.PointerTo(v.Type()).Implements(exposerType) {
+		p := reflect.New(v.Type())
+		p.Elem().Set(v)
+		return p.Interface().(Exposer), true
+	}
+	return nil, false
+}
+
+// fieldByIndex 沿嵌入路径取字段，经过 nil 指针时返回 false
+func fieldByIndex(v reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return reflect.Value{}, false
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, true
+}
+
+// isEmptyValue 与 encoding/json 的 omitempty 规则一致
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Interface, reflect.Ptr:
+		return v.IsZero()
+	}
+	return false
+}
+
+// formatTime 按格式输出 time.Time / *time.Time，零值输出 null
+func formatTime(v reflect.Value, layout string) interface{} {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	t, ok := v.Interface().(time.Time)
+	if !ok {
+		return v.Interface()
+	}
+	if t.IsZero() {
+		return nil
+	}
+	return t.Format(layout)
+}
+
+func hasAnyGroup(want, active []string) bool {
+	for _, w := range want {
+		for _, a := range active {
+			if w == a {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ==========================================
+// 类型分析 (按类型缓存)
+// ==========================================
+
+type serializeField struct {
+	index     []int
+	typ       reflect.Type
+	name      string
+	omitEmpty bool
+	groups    []string
+	format    string
+}
+
+type structPlan struct {
+	fields []serializeField
+	hidden bool // 存在 serialize:"-" 的字段
+}
+
+var (
+	structPlans sync.Map // reflect.Type -> *structPlan
+	needsCache  sync.Map // reflect.Type -> bool
+)
+
+// needsSerialize 类型中是否存在 serialize 标签、Exposer 或 interface (运行时才能确定)，
+// 不存在时原样返回以保留原有的输出
+func needsSerialize(t reflect.Type) bool {
+	if v, ok := needsCache.Load(t); ok {
+		return v.(bool)
+	}
+	needsCache.Store(t, true) // 递归类型：分析过程中保守地视为需要
+	need := computeNeedsSerialize(t)
+	needsCache.Store(t, need)
+	return need
+}
+
+func computeNeedsSerialize(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Interface:
+		return true
+	case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Map:
+		return needsSerialize(t.Elem())
+	case reflect.Struct:
+		if t.Implements(exposerType) || reflect.PointerTo(t).Implements(exposerType) {
+			return true
+		}
+		if isLeafType(t) {
+			return false
+		}
+		plan := structPlanOf(t)
+		if plan.hidden {
+			return true
+		}
+		for _, f := range plan.fields {
+			if len(f.groups) > 0 || f.format != "" || needsSerialize(f.typ) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isLeafType 自带编码方式的类型 (如 time.Time、gorm.DeletedAt) 不展开
+func isLeafType(t reflect.Type) bool {
+	return t.Implements(jsonMarshalerType) || reflect.PointerTo(t).Implements(jsonMarshalerType) ||
+		t.Implements(textMarshalerType) || reflect.PointerTo(t).Implements(textMarshalerType)
+}
+
+func structPlanOf(t reflect.Type) *structPlan {
+	if p, ok := structPlans.Load(t); ok {
+		return p.(*structPlan)
+	}
+	plan := &structPlan{}
+	walkSerializeFields(t, nil, func(f reflect.StructField, index []int) {
+		name := jsonName(f)
+		tag := f.Tag.Get("serialize")
+		if tag == "-" {
+			plan.hidden = true
+		}
+		if name == "" || tag == "-" {
+			return
+		}
+		_, opts, _ := strings.Cut(f.Tag.Get("json"), ",")
+		sf := serializeField{index: index, typ: f.Type, name: name, omitEmpty: strings.Contains(","+opts+",", ",omitempty,")}
+		for _, opt := range strings.Split(tag, ";") {
+			key, val, _ := strings.Cut(strings.TrimSpace(opt), "=")
+			switch key {
+			case "groups":
+				for _, g := range strings.Split(val, ",") {
+					if g = strings.TrimSpace(g); g != "" {
+						sf.groups = append(sf.groups, g)
+					}
+				}
+			case "format":
+				sf.format = val
+			}
+		}
+		plan.fields = append(plan.fields, sf)
+	})
+	structPlans.Store(t, plan)
+	return plan
+}
+
+// walkSerializeFields 与 walkFields 相同的展开规则，并记录字段的索引路径
+func walkSerializeFields(t reflect.Type, prefix []int, fn func(f reflect.StructField, index []int)) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		index := append(append([]int(nil), prefix...), i)
+		ft := f.Type
+		if ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		if f.Anonymous && ft.Kind() == reflect.Struct && f.Tag.Get("json") == "" && !isLeafType(ft) {
+			walkSerializeFields(ft, index, fn)
+			continue
+		}
+		if f.IsExported() {
+			fn(f, index)
+		}
+	}
+}
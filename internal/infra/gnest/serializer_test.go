@@ -0,0 +1,158 @@
+package gnest_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"blog/internal/infra/gnest"
+)
+
+type serialAuthor struct {
+	Name  string `json:"name"`
+	Email string `json:"email" serialize:"groups=admin,self"`
+}
+
+type serialPost struct {
+	ID        int                      `json:"id"`
+	Title     string                   `json:"title"`
+	Secret    string                   `json:"secret" serialize:"-"`
+	Note      string                   `json:"note,omitempty" serialize:"groups=admin"`
+	Summary   string                   `json:"summary,omitempty"`
+	CreatedAt time.Time                `json:"createdAt" serialize:"format=2006-01-02"`
+	UpdatedAt *time.Time               `json:"updatedAt" serialize:"format=2006-01-02 15:04"`
+	Author    *serialAuthor            `json:"author"`
+	Editors   []serialAuthor           `json:"editors"`
+	ByRole    map[string]*serialAuthor `json:"byRole"`
+}
+
+// Expose 覆盖 title 并追加计算字段
+func (p serialPost) Expose(groups []string) []gnest.ExposedField {
+	return []gnest.ExposedField{
+		{Name: "title", Value: strings.ToUpper(p.Title)},
+		{Name: "groupCount", Value: len(groups)},
+	}
+}
+
+func newSerialPost() serialPost {
+	ann := &serialAuthor{Name: "ann", Email: "ann@example.com"}
+	return serialPost{
+		ID:        1,
+		Title:     "hello",
+		Secret:    "s3cret",
+		CreatedAt: time.Date(2024, 5, 1, 10, 30, 0, 0, time.UTC),
+		Author:    ann,
+		Editors:   []serialAuthor{*ann},
+		ByRole:    map[string]*serialAuthor{"owner": ann},
+	}
+}
+
+func TestSerialize(t *testing.T) {
+	updated := time.Date(2024, 6, 2, 8, 0, 0, 0, time.UTC)
+	cases := []struct {
+		name   string
+		post   func(p *serialPost)
+		groups []string
+		want   string
+	}{
+		{
+			name: "no groups",
+			want: `{"id":1,"title":"HELLO","createdAt":"2024-05-01","updatedAt":null,"author":{"name":"ann"},` +
+				`"editors":[{"name":"ann"}],"byRole":{"owner":{"name":"ann"}},"groupCount":0}`,
+		},
+		{
+			name:   "groups reach nested slices, maps and pointers",
+			groups: []string{"self"},
+			want: `{"id":1,"title":"HELLO","createdAt":"2024-05-01","updatedAt":null,"author":{"name":"ann","email":"ann@example.com"},` +
+				`"editors":[{"name":"ann","email":"ann@example.com"}],"byRole":{"owner":{"name":"ann","email":"ann@example.com"}},"groupCount":1}`,
+		},
+		{
+			name:   "omitempty within a group",
+			groups: []string{"admin"},
+			post:   func(p *serialPost) { p.Author, p.Editors, p.ByRole = nil, nil, nil },
+			want:   `{"id":1,"title":"HELLO","createdAt":"2024-05-01","updatedAt":null,"author":null,"editors":null,"byRole":null,"groupCount":1}`,
+		},
+		{
+			name:   "omitempty keeps set values",
+			groups: []string{"admin"},
+			post: func(p *serialPost) {
+				p.Note, p.Summary, p.UpdatedAt = "draft", "short", &updated
+				p.Author, p.Editors, p.ByRole = nil, nil, nil
+			},
+			want: `{"id":1,"title":"HELLO","note":"draft","summary":"short","createdAt":"2024-05-01","updatedAt":"2024-06-02 08:00",` +
+				`"author":null,"editors":null,"byRole":null,"groupCount":1}`,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			post := newSerialPost()
+			if tc.post != nil {
+				tc.post(&post)
+			}
+			got, err := json.Marshal(gnest.Serialize(&post, tc.groups...))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tc.want {
+				t.Errorf("expected\n%s\ngot\n%s", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestSerializePassesThroughPlainValues(t *testing.T) {
+	plain := map[string]int{"a": 1}
+	if got := gnest.Serialize(plain); !reflect.DeepEqual(got, plain) {
+		t.Errorf("expected the value unchanged, got %#v", got)
+	}
+}
+
+func TestSerializerInterceptorGroups(t *testing.T) {
+	app := gnest.NewTestingApp()
+	app.UseGlobalInterceptors(&gnest.SerializerInterceptor{Options: &gnest.SerializerOptions{
+		Groups: func(c *gin.Context) []string {
+			if c.GetHeader("X-Role") == "admin" {
+				return []string{"admin"}
+			}
+			return nil
+		},
+	}})
+	app.GET("/posts/public", func() serialPost { return newSerialPost() })
+	app.GET("/posts/mine", func() serialPost { return newSerialPost() }, gnest.SerializeGroups("self"))
+	if err := app.Compile(); err != nil {
+		t.Fatal(err)
+	}
+	client := app.Client(t)
+
+	cases := []struct {
+		name       string
+		path, role string
+		wantEmail  bool
+		wantGroups int
+	}{
+		{"no groups", "/posts/public", "", false, 0},
+		{"route groups", "/posts/mine", "", true, 1},
+		{"option groups", "/posts/public", "admin", true, 1},
+		{"route and option groups", "/posts/mine", "admin", true, 2},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var body struct {
+				Secret string `json:"secret"`
+				Author struct {
+					Email string `json:"email"`
+				} `json:"author"`
+				GroupCount int `json:"groupCount"`
+			}
+			client.GET(tc.path).Header("X-Role", tc.role).ExpectStatus(http.StatusOK).DecodeInto(&body)
+			if (body.Author.Email != "") != tc.wantEmail || body.GroupCount != tc.wantGroups || body.Secret != "" {
+				t.Errorf("unexpected body %+v", body)
+			}
+		})
+	}
+}
@@ -0,0 +1,199 @@
+package gnest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ==========================================
+// 流式响应 (Streaming Results)
+// ==========================================
+
+// SSEEvent 单条服务端推送事件
+type SSEEvent struct {
+	ID    string        // 事件 ID，客户端断线重连时通过 Last-Event-ID 带回
+	Event string        // 事件名，为空时客户端按 message 处理
+	Data  interface{}   // string / []byte 原样输出，其余类型编码为 JSON
+	Retry time.Duration // 建议客户端的重连间隔
+}
+
+// SSEResult 以 text/event-stream 推送 Events 中的事件，通道关闭或客户端断开时结束；
+// Heartbeat 大于 0 时定期发送注释行，避免代理因空闲断开连接。
+// 客户端断开后不再读取通道，生产者应同时监听请求的 ctx.Done() 以免阻塞
+type SSEResult struct {
+	Events    <-chan SSEEvent
+	Heartbeat time.Duration
+}
+
+// StreamResult 将 Reader 的内容流式写出，Reader 实现 io.Closer 时结束后自动关闭
+type StreamResult struct {
+	Reader        io.Reader
+	ContentType   string // 默认 application/octet-stream
+	ContentLength int64  // 小于等于 0 表示未知长度，使用分块传输
+	FileName      string // 如果不为空，则作为附件下载
+}
+
+// ChunkedResult 由生成器逐块输出，每次 write 后立即刷新；
+// 客户端断开时 ctx 被取消且 write 返回错误，生成器应随之退出
+type ChunkedResult struct {
+	ContentType string // 默认 text/plain; charset=utf-8
+	Generator   func(ctx context.Context, write func(chunk []byte) error) error
+}
+
+// errClientGone 客户端断开连接后写入返回的错误
+var errClientGone = errors.New("client disconnected")
+
+func writeSSE(c *gin.Context, v SSEResult) {
+	h := c.Writer.Header()
+	h.Set("Content-Type", "text/event-stream")
+	h.Set("Cache-Control", "no-cache")
+	h.Set("Connection", "keep-alive")
+	h.Set("X-Accel-Buffering", "no") // 关闭 nginx 缓冲
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+
+	var heartbeat <-chan time.Time
+	if v.Heartbeat > 0 {
+		ticker := time.NewTicker(v.Heartbeat)
+		defer ticker.Stop()
+		heartbeat = ticker.C
+	}
+	ctx := c.Request.Context()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-heartbeat:
+			if _, err := io.WriteString(c.Writer, ": ping\n\n"); err != nil {
+				return
+			}
+			c.Writer.Flush()
+		case ev, ok := <-v.Events:
+			if !ok {
+				return
+			}
+			if _, err := io.WriteString(c.Writer, encodeSSE(ev)); err != nil {
+				return
+			}
+			c.Writer.Flush()
+		}
+	}
+}
+
+// encodeSSE 按 text/event-stream 格式编码事件，多行数据拆分为多个 data 字段
+func encodeSSE(ev SSEEvent) string {
+	var b strings.Builder
+	if ev.ID != "" {
+		b.WriteString("id: " + ev.ID + "\n")
+	}
+	if ev.Event != "" {
+		b.WriteString("event: " + ev.Event + "\n")
+	}
+	if ev.Retry > 0 {
+		b.WriteString("retry: " + strconv.FormatInt(ev.Retry.Milliseconds(), 10) + "\n")
+	}
+	var data string
+	switch d := ev.Data.(type) {
+	case nil:
+	case string:
+		data = d
+	case []byte:
+		data = string(d)
+	default:
+		raw, err := json.Marshal(d)
+		if err != nil {
+			raw, _ = json.Marshal(err.Error())
+		}
+		data = string(raw)
+	}
+	for _, line := range strings.Split(data, "\n") {
+		b.WriteString("data: " + line + "\n")
+	}
+	b.WriteString("\n")
+	return b.String()
+}
+
+func writeStream(c *gin.Context, v StreamResult) {
+	if closer, ok := v.Reader.(io.Closer); ok {
+		defer closer.Close()
+	}
+	contentType := v.ContentType
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	h := c.Writer.Header()
+	h.Set("Content-Type", contentType)
+	if v.ContentLength > 0 {
+		h.Set("Content-Length", strconv.FormatInt(v.ContentLength, 10))
+	}
+	if v.FileName != "" {
+		h.Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", v.FileName))
+	}
+	c.Status(http.StatusOK)
+
+	ctx := c.Request.Context()
+	buf := make([]byte, 32*1024)
+	for {
+		if ctx.Err() != nil {
+			return
+		}
+		n, err := v.Reader.Read(buf)
+		if n > 0 {
+			if _, werr := c.Writer.Write(buf[:n]); werr != nil {
+				return
+			}
+			// 未知长度时逐块刷新，已知长度的大文件交给底层缓冲
+			if v.ContentLength <= 0 {
+				c.Writer.Flush()
+			}
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+func writeChunked(c *gin.Context, v ChunkedResult) error {
+	contentType := v.ContentType
+	if contentType == "" {
+		contentType = "text/plain; charset=utf-8"
+	}
+	c.Writer.Header().Set("Content-Type", contentType)
+	c.Writer.Header().Set("X-Content-Type-Options", "nosniff")
+
+	ctx := c.Request.Context()
+	started := false
+	write := func(chunk []byte) error {
+		if ctx.Err() != nil {
+			return errClientGone
+		}
+		if !started {
+			c.Status(http.StatusOK)
+			started = true
+		}
+		if _, err := c.Writer.Write(chunk); err != nil {
+			return errClientGone
+		}
+		c.Writer.Flush()
+		return nil
+	}
+	err := v.Generator(ctx, write)
+	if err != nil && !started {
+		// 尚未输出任何内容时交给异常过滤器处理
+		return err
+	}
+	if !started {
+		c.Status(http.StatusOK)
+		c.Writer.WriteHeaderNow()
+	}
+	return nil
+}
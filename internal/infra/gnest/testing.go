@@ -0,0 +1,339 @@
+package gnest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+)
+
+// ==========================================
+// 测试模块 (Testing)
+// ==========================================
+
+// TestingApp 对应 Nest 的 Test.createTestingModule：在编译模块前替换 Provider、守卫与拦截器，
+// 再通过 Client 以进程内请求驱动完整的管道 (守卫 / 拦截器 / 管道 / 过滤器)
+//
+// 被替换的 Provider 须由传入的模块 (或其导入) 提供；全局模块 (如 InfraModule) 中的 Provider
+// 需要一并传入该模块才能替换，通常直接替换业务依赖的接口更简单：
+//
+//	app := gnest.NewTestingApp(router.AuthModule, configModule).
+//		OverrideProvider((*user.Repository)(nil), newMemoryRepository()).
+//		OverrideGuard((*guards.AuthGuard)(nil), allowAll)
+//	if err := app.Compile(); err != nil {
+//		t.Fatal(err)
+//	}
+//	app.Client(t).POST("/auth/register").JSON(dto).ExpectStatus(200).DecodeInto(&u)
+type TestingApp struct {
+	*GnestApp
+	modules []*Module
+	hooks   bool // Compile 时是否执行启动钩子
+}
+
+// NewTestingApp 以给定模块创建测试应用，全局增强器可在 Compile 前通过内嵌的 GnestApp 注册
+func NewTestingApp(modules ...*Module) *TestingApp {
+	app := New()
+	app.overrides = make(map[token]interface{})
+	app.guardOverrides = make(map[reflect.Type]CanActivate)
+	app.interceptorOverrides = make(map[reflect.Type]NestInterceptor)
+	return &TestingApp{GnestApp: app, modules: modules}
+}
+
+// OverrideProvider 替换令牌 target (typed nil / reflect.Type / 实例 / Named 等 Provider) 对应的 Provider，
+// 接口令牌可写作 (*Store)(nil)。fake 为实例时原样使用、不再注入字段；
+// 也可以是 Factory(...)，其返回值须可赋值给令牌类型
+func (t *TestingApp) OverrideProvider(target, fake interface{}) *TestingApp {
+	tok := tokenOf(target)
+	if tok.typ != nil && tok.typ.Kind() == reflect.Ptr && tok.typ.Elem().Kind() == reflect.Interface {
+		tok.typ = tok.typ.Elem()
+	}
+	t.overrides[tok] = fake
+	// New 中已注册的内置 Provider (Reflector / EventBus / 命令与查询总线) 立即替换
+	if def, ok := t.providers[tok]; ok && def.module == nil {
+		if err := t.applyOverride(def); err != nil {
+			t.errs = append(t.errs, err)
+		}
+	}
+	return t
+}
+
+// OverrideGuard 将所有类型与 target 相同的守卫 (方法级 / 组级 / 全局) 替换为 fake
+func (t *TestingApp) OverrideGuard(target interface{}, fake CanActivate) *TestingApp {
+	t.guardOverrides[reflect.TypeOf(target)] = fake
+	return t
+}
+
+// OverrideInterceptor 将所有类型与 target 相同的拦截器替换为 fake
+func (t *TestingApp) OverrideInterceptor(target interface{}, fake NestInterceptor) *TestingApp {
+	t.interceptorOverrides[reflect.TypeOf(target)] = fake
+	return t
+}
+
+// WithLifecycleHooks 让 Compile 执行 OnModuleInit / OnApplicationBootstrap 并启动微服务，
+// 测试结束后应调用 Shutdown。默认不执行，避免定时任务、消息消费等在测试中意外运行
+func (t *TestingApp) WithLifecycleHooks() *TestingApp {
+	t.hooks = true
+	return t
+}
+
+// Compile 编译模块并挂载路由 (启用 WithLifecycleHooks 时同时执行启动钩子)；
+// 未命中任何 Provider 的替换会作为错误返回，避免测试悄悄使用真实依赖
+func (t *TestingApp) Compile() error {
+	root := &Module{Name: "TestingModule", Imports: t.modules}
+	if err := t.registerModule(root); err != nil {
+		return err
+	}
+	if t.hooks {
+		if err := t.Bootstrap(context.Background()); err != nil {
+			return err
+		}
+	} else if err := t.Init(); err != nil {
+		return err
+	}
+	var errs []error
+	for tok := range t.overrides {
+		if !t.overridden[tok] {
+			errs = append(errs, fmt.Errorf("OverrideProvider: %v is not provided by any module", tok))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// applyOverride 在 Provider 登记时替换为测试替身
+func (app *GnestApp) applyOverride(def *providerDef) error {
+	fake, ok := app.overrides[def.tok]
+	if !ok {
+		return nil
+	}
+	if app.overridden == nil {
+		app.overridden = make(map[token]bool)
+	}
+	app.overridden[def.tok] = true
+
+	p := toProvider(fake)
+	if p.err != nil {
+		return fmt.Errorf("override %v: %w", def.tok, p.err)
+	}
+	typ := def.tok.typ
+	if p.factory.IsValid() {
+		if out := p.factory.Type().Out(0); !out.AssignableTo(typ) {
+			return fmt.Errorf("override %v: factory returns %v", def.tok, out)
+		}
+		def.factory, def.value = p.factory, reflect.Value{}
+	} else {
+		if !p.value.Type().AssignableTo(typ) {
+			return fmt.Errorf("override %v: %v is not assignable", def.tok, p.value.Type())
+		}
+		// 包装成无参工厂：替身视为已构建完成，不参与字段注入
+		v := p.value
+		def.factory = reflect.MakeFunc(reflect.FuncOf(nil, []reflect.Type{typ}, false), func([]reflect.Value) []reflect.Value {
+			out := reflect.New(typ).Elem()
+			out.Set(v)
+			return []reflect.Value{out}
+		})
+		def.value = reflect.Value{}
+	}
+	def.scope = p.scope
+	return nil
+}
+
+func (app *GnestApp) overrideGuards(gs []CanActivate) []CanActivate {
+	if len(app.guardOverrides) == 0 {
+		return gs
+	}
+	out := make([]CanActivate, len(gs))
+	for i, g := range gs {
+		if fake, ok := app.guardOverrides[reflect.TypeOf(g)]; ok {
+			g = fake
+		}
+		out[i] = g
+	}
+	return out
+}
+
+func (app *GnestApp) overrideInterceptors(is []NestInterceptor) []NestInterceptor {
+	if len(app.interceptorOverrides) == 0 {
+		return is
+	}
+	out := make([]NestInterceptor, len(is))
+	for i, in := range is {
+		if fake, ok := app.interceptorOverrides[reflect.TypeOf(in)]; ok {
+			in = fake
+		}
+		out[i] = in
+	}
+	return out
+}
+
+// ==========================================
+// 进程内 HTTP 客户端 (Test Client)
+// ==========================================
+
+// TB 是 *testing.T / *testing.B 的子集，断言失败时终止当前测试
+type TB interface {
+	Helper()
+	Fatalf(format string, args ...interface{})
+}
+
+// TestClient 基于 httptest 的进程内客户端，请求直接交给 gin.Engine 处理
+type TestClient struct {
+	app    *GnestApp
+	t      TB
+	header http.Header
+}
+
+// Client 创建测试客户端
+func (app *GnestApp) Client(t TB) *TestClient {
+	return &TestClient{app: app, t: t, header: make(http.Header)}
+}
+
+// SetHeader 为该客户端发出的所有请求设置默认请求头，如 Authorization
+func (tc *TestClient) SetHeader(key, value string) *TestClient {
+	tc.header.Set(key, value)
+	return tc
+}
+
+func (tc *TestClient) GET(path string) *TestRequest    { return tc.Request(http.MethodGet, path) }
+func (tc *TestClient) POST(path string) *TestRequest   { return tc.Request(http.MethodPost, path) }
+func (tc *TestClient) PUT(path string) *TestRequest    { return tc.Request(http.MethodPut, path) }
+func (tc *TestClient) PATCH(path string) *TestRequest  { return tc.Request(http.MethodPatch, path) }
+func (tc *TestClient) DELETE(path string) *TestRequest { return tc.Request(http.MethodDelete, path) }
+
+// Request 构造任意方法的请求
+func (tc *TestClient) Request(method, path string) *TestRequest {
+	return &TestRequest{client: tc, method: method, path: path, header: tc.header.Clone(), query: make(url.Values)}
+}
+
+// TestRequest 链式构造请求并断言响应；首次调用 Expect* / DecodeInto / Response 时发出请求
+type TestRequest struct {
+	client *TestClient
+	method string
+	path   string
+	header http.Header
+	query  url.Values
+	body   []byte
+	resp   *httptest.ResponseRecorder
+}
+
+func (r *TestRequest) Header(key, value string) *TestRequest {
+	r.header.Set(key, value)
+	return r
+}
+
+func (r *TestRequest) Query(key, value string) *TestRequest {
+	r.query.Add(key, value)
+	return r
+}
+
+// JSON 以 JSON 编码请求体
+func (r *TestRequest) JSON(v interface{}) *TestRequest {
+	b, err := json.Marshal(v)
+	if err != nil {
+		r.client.t.Helper()
+		r.client.t.Fatalf("%s %s: encode JSON body: %v", r.method, r.path, err)
+	}
+	r.body = b
+	r.header.Set("Content-Type", "application/json")
+	return r
+}
+
+// Form 以 application/x-www-form-urlencoded 编码请求体
+func (r *TestRequest) Form(values url.Values) *TestRequest {
+	r.body = []byte(values.Encode())
+	r.header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return r
+}
+
+// Body 设置原始请求体
+func (r *TestRequest) Body(contentType string, body []byte) *TestRequest {
+	r.body = body
+	r.header.Set("Content-Type", contentType)
+	return r
+}
+
+// Response 发出请求 (仅一次) 并返回响应记录
+func (r *TestRequest) Response() *httptest.ResponseRecorder {
+	if r.resp != nil {
+		return r.resp
+	}
+	target := r.path
+	if len(r.query) > 0 {
+		sep := "?"
+		if strings.Contains(target, "?") {
+			sep = "&"
+		}
+		target += sep + r.query.Encode()
+	}
+	var body io.Reader
+	if r.body != nil {
+		body = bytes.NewReader(r.body)
+	}
+	req := httptest.NewRequest(r.method, target, body)
+	req.Header = r.header
+	r.resp = httptest.NewRecorder()
+	r.client.app.Engine.ServeHTTP(r.resp, req)
+	return r.resp
+}
+
+// ExpectStatus 断言状态码，失败时输出响应体便于定位
+func (r *TestRequest) ExpectStatus(code int) *TestRequest {
+	resp := r.Response()
+	if resp.Code != code {
+		r.client.t.Helper()
+		r.client.t.Fatalf("%s %s: expected status %d, got %d: %s", r.method, r.path, code, resp.Code, resp.Body.String())
+	}
+	return r
+}
+
+// ExpectHeader 断言响应头
+func (r *TestRequest) ExpectHeader(key, value string) *TestRequest {
+	if got := r.Response().Header().Get(key); got != value {
+		r.client.t.Helper()
+		r.client.t.Fatalf("%s %s: expected header %s=%q, got %q", r.method, r.path, key, value, got)
+	}
+	return r
+}
+
+// ExpectErrorCode 断言统一错误响应体中的 code，启用响应信封时从信封的 data 中读取
+func (r *TestRequest) ExpectErrorCode(code string) *TestRequest {
+	body := errorBodyOf(r.Response().Body.Bytes())
+	if body.Code != code {
+		r.client.t.Helper()
+		r.client.t.Fatalf("%s %s: expected error code %q, got %q", r.method, r.path, code, body.Code)
+	}
+	return r
+}
+
+// errorBodyOf 解析 ErrorBody，顶层不是时在信封的各字段中查找
+func errorBodyOf(data []byte) ErrorBody {
+	var body ErrorBody
+	if json.Unmarshal(data, &body) == nil && body.StatusCode != 0 {
+		return body
+	}
+	var fields map[string]json.RawMessage
+	_ = json.Unmarshal(data, &fields)
+	for _, raw := range fields {
+		var inner ErrorBody
+		if json.Unmarshal(raw, &inner) == nil && inner.StatusCode != 0 {
+			return inner
+		}
+	}
+	return body
+}
+
+// DecodeInto 将 JSON 响应体解码到 v
+func (r *TestRequest) DecodeInto(v interface{}) *TestRequest {
+	resp := r.Response()
+	if err := json.Unmarshal(resp.Body.Bytes(), v); err != nil {
+		r.client.t.Helper()
+		r.client.t.Fatalf("%s %s: decode response: %v: %s", r.method, r.path, err, resp.Body.String())
+	}
+	return r
+}
@@ -0,0 +1,62 @@
+package gnest_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"blog/internal/infra/gnest"
+)
+
+type busUser struct {
+	Events *gnest.EventBus
+}
+
+func TestOverrideBuiltinProvider(t *testing.T) {
+	fake := gnest.NewEventBus(gnest.EventBusOptions{})
+	consumer := &busUser{}
+	app := gnest.NewTestingApp(&gnest.Module{Name: "Bus", Providers: []interface{}{consumer}}).
+		OverrideProvider((*gnest.EventBus)(nil), fake)
+	if err := app.Compile(); err != nil {
+		t.Fatal(err)
+	}
+	if consumer.Events != fake {
+		t.Error("expected the built-in EventBus to be replaced by the override")
+	}
+}
+
+func TestOverrideUnknownProviderFails(t *testing.T) {
+	type missing struct{}
+	app := gnest.NewTestingApp().OverrideProvider((*missing)(nil), &missing{})
+	if err := app.Compile(); err == nil || !strings.Contains(err.Error(), "not provided by any module") {
+		t.Fatalf("expected an unused override error, got %v", err)
+	}
+}
+
+type bootCounter struct{ boots int }
+
+func (b *bootCounter) OnApplicationBootstrap(context.Context) error {
+	b.boots++
+	return nil
+}
+
+func TestCompileRunsHooksOnlyWhenAsked(t *testing.T) {
+	plain := &bootCounter{}
+	app := gnest.NewTestingApp(&gnest.Module{Name: "Plain", Providers: []interface{}{plain}})
+	if err := app.Compile(); err != nil {
+		t.Fatal(err)
+	}
+	if plain.boots != 0 {
+		t.Errorf("expected no bootstrap hooks by default, got %d", plain.boots)
+	}
+
+	hooked := &bootCounter{}
+	app = gnest.NewTestingApp(&gnest.Module{Name: "Hooked", Providers: []interface{}{hooked}}).WithLifecycleHooks()
+	if err := app.Compile(); err != nil {
+		t.Fatal(err)
+	}
+	defer app.Shutdown(context.Background(), "test")
+	if hooked.boots != 1 {
+		t.Errorf("expected the bootstrap hook to run once, got %d", hooked.boots)
+	}
+}
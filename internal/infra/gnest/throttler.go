@@ -0,0 +1,318 @@
+package gnest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ==========================================
+// 限流 (Throttler)
+// ==========================================
+
+// 注册为全局守卫后，路由按命名策略限流，未声明策略的路由使用 "default" 策略：
+//
+//	throttler := &gnest.ThrottlerGuard{} // Options 从容器注入
+//	app.Provide(throttler)
+//	app.UseGlobalGuards(throttler)
+//
+//	rg.POST("/login", ctrl.Login, gnest.Throttle("login"))
+//	rg.GET("/health", ctrl.Health, gnest.SkipThrottle())
+//
+// 响应携带 RateLimit-Limit / RateLimit-Remaining / RateLimit-Reset / RateLimit-Policy，
+// 超出限制时返回 429 与 Retry-After。存储出错时放行并记录日志，不因限流影响可用性
+
+// 限流相关的元数据键
+const (
+	ThrottleKey     = "throttle"
+	SkipThrottleKey = "skipThrottle"
+)
+
+// DefaultThrottlePolicy 未声明 Throttle 的路由使用的策略名
+const DefaultThrottlePolicy = "default"
+
+// Throttle 指定路由 (组) 使用的限流策略
+func Throttle(policy string) Metadata {
+	return SetMetadata(ThrottleKey, policy)
+}
+
+// SkipThrottle 路由 (组) 不限流
+func SkipThrottle() Metadata {
+	return SetMetadata(SkipThrottleKey, true)
+}
+
+// ThrottleAlgorithm 限流算法
+type ThrottleAlgorithm string
+
+const (
+	FixedWindow   ThrottleAlgorithm = "fixed-window"   // 固定窗口计数
+	SlidingWindow ThrottleAlgorithm = "sliding-window" // 滑动窗口日志，窗口内最多 Limit 次
+	TokenBucket   ThrottleAlgorithm = "token-bucket"   // 容量 Limit，每 Window 匀速补满，允许突发
+)
+
+// ThrottlePolicy 命名的限流策略：每 Window 最多 Limit 次
+type ThrottlePolicy struct {
+	Limit     int
+	Window    time.Duration
+	Algorithm ThrottleAlgorithm // 默认 FixedWindow
+	// By 限流键的组成，默认 ["ip"]，可选 ip、header:<名称>、query:<名称>、param:<名称>、body:<字段>
+	// 如登录按 IP + 用户名限流：["ip", "body:userName"]
+	By []string
+}
+
+// ThrottleResult 一次请求的限流结果
+type ThrottleResult struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	Reset      time.Duration // 额度完全恢复所需时间
+	RetryAfter time.Duration // 被拒绝时距下次可用的时间
+}
+
+// ThrottleStore 限流存储，Take 需原子地判断并扣减额度
+type ThrottleStore interface {
+	Take(ctx context.Context, key string, policy ThrottlePolicy) (ThrottleResult, error)
+}
+
+// ThrottlerOptions 限流配置
+type ThrottlerOptions struct {
+	Policies map[string]ThrottlePolicy
+	Store    ThrottleStore // 默认内存存储，多实例部署时使用 redis.NewThrottleStore
+	Prefix   string        // 键前缀，默认 "gnest:throttle:"
+}
+
+// ThrottlerGuard 限流守卫，Options 未注入时不限流
+type ThrottlerGuard struct {
+	Options *ThrottlerOptions
+
+	once   sync.Once
+	store  ThrottleStore
+	prefix string
+}
+
+func (g *ThrottlerGuard) init() {
+	g.store, g.prefix = NewMemoryThrottleStore(), "gnest:throttle:"
+	if g.Options == nil {
+		return
+	}
+	if g.Options.Store != nil {
+		g.store = g.Options.Store
+	}
+	if g.Options.Prefix != "" {
+		g.prefix = g.Options.Prefix
+	}
+}
+
+func (g *ThrottlerGuard) CanActivate(c *gin.Context) bool {
+	if g.Options == nil || messageContextOf(c) != nil {
+		return true
+	}
+	if skip, _ := GetMetadata[bool](c, SkipThrottleKey); skip {
+		return true
+	}
+	g.once.Do(g.init)
+
+	name, ok := GetMetadata[string](c, ThrottleKey)
+	if !ok {
+		name = DefaultThrottlePolicy
+	}
+	policy, ok := g.Options.Policies[name]
+	if !ok {
+		if name == DefaultThrottlePolicy {
+			return true
+		}
+		return Deny(c, fmt.Errorf("throttler: unknown policy %q", name))
+	}
+	if policy.Limit <= 0 || policy.Window <= 0 {
+		return Deny(c, fmt.Errorf("throttler: policy %q requires a positive limit and window", name))
+	}
+	if policy.Algorithm == "" {
+		policy.Algorithm = FixedWindow
+	}
+
+	key := g.prefix + name + ":" + throttleKey(c, policy.By)
+	res, err := g.store.Take(c.Request.Context(), key, policy)
+	if err != nil {
+		log.Printf("[Gnest] throttler %s: %v", key, err)
+		return true
+	}
+	c.Header("RateLimit-Limit", strconv.Itoa(res.Limit))
+	c.Header("RateLimit-Remaining", strconv.Itoa(res.Remaining))
+	c.Header("RateLimit-Reset", strconv.Itoa(ceilSeconds(res.Reset)))
+	c.Header("RateLimit-Policy", fmt.Sprintf("%d;w=%d", policy.Limit, ceilSeconds(policy.Window)))
+	if !res.Allowed {
+		c.Header("Retry-After", strconv.Itoa(ceilSeconds(res.RetryAfter)))
+		return Deny(c, TooManyRequests("Too Many Requests"))
+	}
+	return true
+}
+
+// throttleKey 按 By 拼接限流键
+func throttleKey(c *gin.Context, by []string) string {
+	if len(by) == 0 {
+		return c.ClientIP()
+	}
+	parts := make([]string, len(by))
+	for i, src := range by {
+		kind, name, _ := strings.Cut(src, ":")
+		switch kind {
+		case "ip":
+			parts[i] = c.ClientIP()
+		case "header":
+			parts[i] = c.GetHeader(name)
+		case "query":
+			parts[i] = c.Query(name)
+		case "param":
+			parts[i] = c.Param(name)
+		case "body":
+			parts[i] = bodyField(c, name)
+		}
+	}
+	return strings.Join(parts, ":")
+}
+
+// maxThrottleBody 限流键读取 JSON 请求体的上限，超出时不解析该字段
+const maxThrottleBody = 1 << 20
+
+// bodyField 读取 JSON 或表单请求体中的字段，读取后恢复请求体供后续绑定；
+// JSON 请求体最多读取 maxThrottleBody 字节，更大的请求体按未携带该字段处理
+func bodyField(c *gin.Context, name string) string {
+	if c.ContentType() != gin.MIMEJSON {
+		return c.PostForm(name)
+	}
+	body := c.Request.Body
+	if body == nil {
+		return ""
+	}
+	data, err := io.ReadAll(io.LimitReader(body, maxThrottleBody+1))
+	// 未读完的部分接在已读内容之后，后续绑定仍能看到完整的请求体
+	c.Request.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(data), body), body}
+	if err != nil || len(data) > maxThrottleBody {
+		return ""
+	}
+	var fields map[string]interface{}
+	if json.Unmarshal(data, &fields) != nil {
+		return ""
+	}
+	if v, ok := fields[name]; ok && v != nil {
+		return fmt.Sprint(v)
+	}
+	return ""
+}
+
+func ceilSeconds(d time.Duration) int {
+	if d <= 0 {
+		return 0
+	}
+	return int(math.Ceil(d.Seconds()))
+}
+
+// ==========================================
+// 内存存储 (Memory Store)
+// ==========================================
+
+// MemoryThrottleStore 单实例的限流存储，过期的键定期清理
+type MemoryThrottleStore struct {
+	mu        sync.Mutex
+	entries   map[string]*throttleEntry
+	lastSweep time.Time
+}
+
+type throttleEntry struct {
+	count   int         // 固定窗口计数
+	start   time.Time   // 固定窗口起点
+	hits    []time.Time // 滑动窗口内的请求时间
+	tokens  float64     // 令牌桶余量
+	last    time.Time   // 令牌桶上次补充时间
+	expires time.Time
+}
+
+func NewMemoryThrottleStore() *MemoryThrottleStore {
+	return &MemoryThrottleStore{entries: make(map[string]*throttleEntry), lastSweep: time.Now()}
+}
+
+func (s *MemoryThrottleStore) Take(_ context.Context, key string, p ThrottlePolicy) (ThrottleResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	s.sweep(now)
+	e, ok := s.entries[key]
+	if !ok {
+		e = &throttleEntry{start: now, tokens: float64(p.Limit), last: now}
+		s.entries[key] = e
+	}
+	res := ThrottleResult{Limit: p.Limit}
+
+	switch p.Algorithm {
+	case SlidingWindow:
+		i := 0
+		for i < len(e.hits) && now.Sub(e.hits[i]) >= p.Window {
+			i++
+		}
+		e.hits = e.hits[i:]
+		if len(e.hits) < p.Limit {
+			e.hits = append(e.hits, now)
+			res.Allowed = true
+		}
+		res.Remaining = p.Limit - len(e.hits)
+		if len(e.hits) > 0 {
+			res.Reset = e.hits[0].Add(p.Window).Sub(now)
+		}
+		if !res.Allowed {
+			res.RetryAfter = res.Reset
+		}
+	case TokenBucket:
+		rate := float64(p.Limit) / float64(p.Window) // 每纳秒补充的令牌
+		e.tokens = math.Min(float64(p.Limit), e.tokens+float64(now.Sub(e.last))*rate)
+		e.last = now
+		if e.tokens >= 1 {
+			e.tokens--
+			res.Allowed = true
+		} else {
+			res.RetryAfter = time.Duration((1 - e.tokens) / rate)
+		}
+		res.Remaining = int(e.tokens)
+		res.Reset = time.Duration((float64(p.Limit) - e.tokens) / rate)
+	case FixedWindow, "":
+		if now.Sub(e.start) >= p.Window {
+			e.count, e.start = 0, now
+		}
+		e.count++
+		res.Allowed = e.count <= p.Limit
+		res.Remaining = max(p.Limit-e.count, 0)
+		res.Reset = e.start.Add(p.Window).Sub(now)
+		if !res.Allowed {
+			res.RetryAfter = res.Reset
+		}
+	default:
+		return res, fmt.Errorf("throttler: unsupported algorithm %q", p.Algorithm)
+	}
+	e.expires = now.Add(p.Window)
+	return res, nil
+}
+
+// sweep 每分钟清理一次超过窗口未访问的键，调用方持有锁
+func (s *MemoryThrottleStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < time.Minute {
+		return
+	}
+	s.lastSweep = now
+	for key, e := range s.entries {
+		if now.After(e.expires) {
+			delete(s.entries, key)
+		}
+	}
+}
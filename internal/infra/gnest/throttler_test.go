@@ -0,0 +1,43 @@
+package gnest_test
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"blog/internal/infra/gnest"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestThrottleByBodyField(t *testing.T) {
+	guard := &gnest.ThrottlerGuard{Options: &gnest.ThrottlerOptions{Policies: map[string]gnest.ThrottlePolicy{
+		gnest.DefaultThrottlePolicy: {Limit: 1, Window: time.Minute, By: []string{"body:userName"}},
+	}}}
+	app := gnest.NewTestingApp()
+	app.UseGlobalGuards(guard)
+	app.POST("/login", func(c *gin.Context) int {
+		data, _ := io.ReadAll(c.Request.Body)
+		return len(data)
+	})
+	if err := app.Compile(); err != nil {
+		t.Fatal(err)
+	}
+
+	login := func(body string) *gnest.TestRequest {
+		return app.Client(t).POST("/login").Body(gin.MIMEJSON, []byte(body))
+	}
+	login(`{"userName":"alice"}`).ExpectStatus(http.StatusOK)
+	login(`{"userName":"bob"}`).ExpectStatus(http.StatusOK)
+	login(`{"userName":"alice"}`).ExpectStatus(http.StatusTooManyRequests)
+
+	// 超过读取上限的请求体不解析字段，但处理器仍能读到完整内容
+	large := `{"userName":"carol","bio":"` + strings.Repeat("x", 2<<20) + `"}`
+	var n int
+	login(large).ExpectStatus(http.StatusOK).DecodeInto(&n)
+	if n != len(large) {
+		t.Errorf("expected the handler to read %d bytes, got %d", len(large), n)
+	}
+}
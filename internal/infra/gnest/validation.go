@@ -0,0 +1,243 @@
+package gnest
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/locales/en"
+	"github.com/go-playground/locales/zh"
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+	en_translations "github.com/go-playground/validator/v10/translations/en"
+	zh_translations "github.com/go-playground/validator/v10/translations/zh"
+)
+
+// ==========================================
+// 参数校验与错误本地化 (Validation)
+// ==========================================
+
+// 校验失败的默认提示语，按语言区分
+func defaultValidationMessages() map[string]string {
+	return map[string]string{
+		"en": "validation failed",
+		"zh": "参数校验失败",
+	}
+}
+
+// newTranslator 创建内置 zh / en 翻译器，未匹配 Accept-Language 时回退到 en；
+// 翻译文本注册在翻译器上，每个校验器需要独立的一份
+func newTranslator() *ut.UniversalTranslator {
+	return ut.New(en.New(), en.New(), zh.New())
+}
+
+// validators 返回需要同步配置的校验器，按校验顺序排列：
+// binding tag 的校验器与 gnest 自身的 validate (validate tag)，均属于本应用
+func (app *GnestApp) validators() []*validator.Validate {
+	return []*validator.Validate{app.bindingValidate, app.validate}
+}
+
+// newBindingValidator 创建校验 binding tag 的校验器，与 gin 默认引擎的配置一致。
+// 每个应用各自持有，自定义规则与翻译不会写入 gin 进程级的 binding.Validator
+func newBindingValidator() *validator.Validate {
+	v := validator.New()
+	v.SetTagName("binding")
+	return v
+}
+
+// ginValidationGate 包装 gin 进程级的 binding.Validator：gnest 绑定中的对象跳过 gin 的校验，
+// 改由所属应用的校验器处理；其余直接调用 c.ShouldBind 的代码行为不变
+type ginValidationGate struct {
+	binding.StructValidator
+	skip sync.Map
+}
+
+func (g *ginValidationGate) ValidateStruct(obj any) error {
+	if _, ok := g.skip.Load(obj); ok {
+		return nil
+	}
+	return g.StructValidator.ValidateStruct(obj)
+}
+
+var (
+	ginGateOnce sync.Once
+	ginGate     *ginValidationGate
+)
+
+// installGinValidationGate 在创建应用时安装一次，避免与处理中的请求并发修改 binding.Validator
+func installGinValidationGate() {
+	ginGateOnce.Do(func() {
+		ginGate = &ginValidationGate{StructValidator: binding.Validator}
+		binding.Validator = ginGate
+	})
+}
+
+// skipGinValidation 在绑定 obj 期间关闭 gin 对它的校验，返回恢复函数
+func skipGinValidation(obj interface{}) func() {
+	ginGate.skip.Store(obj, struct{}{})
+	return func() { ginGate.skip.Delete(obj) }
+}
+
+// setupValidation 注册 JSON 字段名与内置翻译
+func (app *GnestApp) setupValidation() {
+	installGinValidationGate()
+	for _, v := range app.validators() {
+		v.RegisterTagNameFunc(func(f reflect.StructField) string {
+			return jsonName(f)
+		})
+		uni := newTranslator()
+		app.translators[v] = uni
+		enTrans, _ := uni.GetTranslator("en")
+		if err := en_translations.RegisterDefaultTranslations(v, enTrans); err != nil {
+			app.errs = append(app.errs, fmt.Errorf("register en translations: %w", err))
+		}
+		zhTrans, _ := uni.GetTranslator("zh")
+		if err := zh_translations.RegisterDefaultTranslations(v, zhTrans); err != nil {
+			app.errs = append(app.errs, fmt.Errorf("register zh translations: %w", err))
+		}
+	}
+}
+
+// Validator 返回 gnest 使用的校验器，用于在服务中手动校验
+func (app *GnestApp) Validator() *validator.Validate {
+	return app.validate
+}
+
+// RegisterValidation 注册自定义校验规则，同时作用于 binding 与 validate 两种 tag
+func (app *GnestApp) RegisterValidation(tag string, fn validator.Func, callValidationEvenIfNull ...bool) *GnestApp {
+	for _, v := range app.validators() {
+		if err := v.RegisterValidation(tag, fn, callValidationEvenIfNull...); err != nil {
+			app.errs = append(app.errs, fmt.Errorf("register validation %q: %w", tag, err))
+			break
+		}
+	}
+	return app
+}
+
+// RegisterTranslation 为校验规则注册某个语言的提示语，{0} 为字段名，{1} 为规则参数：
+//
+//	app.RegisterTranslation("mobile", "zh", "{0}必须是有效的手机号")
+func (app *GnestApp) RegisterTranslation(tag, locale, text string) *GnestApp {
+	register := func(t ut.Translator) error {
+		return t.Add(tag, text, true)
+	}
+	translate := func(t ut.Translator, fe validator.FieldError) string {
+		msg, err := t.T(fe.Tag(), fe.Field(), fe.Param())
+		if err != nil {
+			return fe.Error()
+		}
+		return msg
+	}
+	for _, v := range app.validators() {
+		trans, ok := app.translators[v].GetTranslator(locale)
+		if !ok {
+			app.errs = append(app.errs, fmt.Errorf("register translation %q: unsupported locale %q", tag, locale))
+			break
+		}
+		if err := v.RegisterTranslation(tag, trans, register, translate); err != nil {
+			app.errs = append(app.errs, fmt.Errorf("register translation %q: %w", tag, err))
+			break
+		}
+	}
+	return app
+}
+
+// translatorFor 根据 Accept-Language 选择校验器 v 对应的翻译器，如 "zh-CN,zh;q=0.9,en;q=0.8"；
+// c 为 nil (请求之外，如命令总线) 时使用默认语言
+func (app *GnestApp) translatorFor(c *gin.Context, v *validator.Validate) ut.Translator {
+	var locales []string
+	var accept string
+	if c != nil {
+		accept = c.GetHeader("Accept-Language")
+	}
+	for _, part := range strings.Split(accept, ",") {
+		tag, _, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if tag == "" || tag == "*" {
+			continue
+		}
+		tag = strings.ReplaceAll(tag, "-", "_")
+		locales = append(locales, tag)
+		if base, _, ok := strings.Cut(tag, "_"); ok {
+			locales = append(locales, base)
+		}
+	}
+	trans, _ := app.translators[v].FindTranslator(locales...)
+	return trans
+}
+
+// validationException 将校验错误转换为带字段明细的 400 异常，提示语按请求语言翻译
+func (app *GnestApp) validationException(c *gin.Context, v *validator.Validate, root reflect.Type, err error) *HttpException {
+	var ve validator.ValidationErrors
+	if !errors.As(err, &ve) {
+		return ToHttpException(err)
+	}
+	trans := app.translatorFor(c, v)
+	details := make([]FieldError, 0, len(ve))
+	for _, fe := range ve {
+		details = append(details, FieldError{
+			Field:   fieldPath(root, fe.StructNamespace()),
+			Rule:    fe.Tag(),
+			Param:   fe.Param(),
+			Message: fe.Translate(trans),
+		})
+	}
+	message, ok := app.validationMessages[trans.Locale()]
+	if !ok {
+		message = app.validationMessages["en"]
+	}
+	return BadRequest(message).WithCode(CodeValidationFailed).WithDetails(details).WithCause(err)
+}
+
+// fieldPath 将 Go 命名空间 (CreateUserDTO.Profile.Items[0].Name) 转为 JSON 路径 (profile.items[0].name)，
+// 没有 json tag 的匿名嵌入结构体与 JSON 一样被展开，不出现在路径中
+func fieldPath(root reflect.Type, ns string) string {
+	segs := strings.Split(ns, ".")
+	if len(segs) > 0 {
+		segs = segs[1:] // 去掉根结构体名
+	}
+	var path []string
+	t := root
+	for _, seg := range segs {
+		name, index, _ := strings.Cut(seg, "[")
+		if index != "" {
+			index = "[" + index
+		}
+		t = derefType(t)
+		if t == nil || t.Kind() != reflect.Struct {
+			path = append(path, seg)
+			t = nil
+			continue
+		}
+		f, ok := t.FieldByName(name)
+		if !ok {
+			path = append(path, seg)
+			t = nil
+			continue
+		}
+		t = f.Type
+		if index != "" {
+			// 切片 / 数组 / map 元素
+			for i := strings.Count(index, "["); i > 0 && t != nil; i-- {
+				if t = derefType(t); t.Kind() == reflect.Slice || t.Kind() == reflect.Array || t.Kind() == reflect.Map {
+					t = t.Elem()
+				}
+			}
+		}
+		if f.Anonymous && f.Tag.Get("json") == "" && index == "" {
+			continue
+		}
+		path = append(path, jsonName(f)+index)
+	}
+	return strings.Join(path, ".")
+}
+
+func derefType(t reflect.Type) reflect.Type {
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
@@ -0,0 +1,43 @@
+package gnest_test
+
+import (
+	"net/http"
+	"testing"
+
+	"blog/internal/infra/gnest"
+
+	"github.com/go-playground/validator/v10"
+)
+
+type codeDTO struct {
+	Code string `json:"code" binding:"required,code"`
+}
+
+// newCodeApp 每个应用以不同的规则与提示语注册同名校验 code
+func newCodeApp(t *testing.T, want, message string) *gnest.TestingApp {
+	app := gnest.NewTestingApp()
+	app.RegisterValidation("code", func(fl validator.FieldLevel) bool { return fl.Field().String() == want })
+	app.RegisterTranslation("code", "en", message)
+	app.POST("/codes", func(dto *codeDTO) string { return dto.Code })
+	if err := app.Compile(); err != nil {
+		t.Fatal(err)
+	}
+	return app
+}
+
+func TestValidationIsPerApp(t *testing.T) {
+	a := newCodeApp(t, "a", "{0} must be a")
+	b := newCodeApp(t, "b", "{0} must be b")
+
+	a.Client(t).POST("/codes").JSON(map[string]string{"code": "a"}).ExpectStatus(http.StatusOK)
+	b.Client(t).POST("/codes").JSON(map[string]string{"code": "b"}).ExpectStatus(http.StatusOK)
+
+	var body struct {
+		Details []gnest.FieldError `json:"details"`
+	}
+	a.Client(t).POST("/codes").JSON(map[string]string{"code": "b"}).
+		ExpectStatus(http.StatusBadRequest).ExpectErrorCode(gnest.CodeValidationFailed).DecodeInto(&body)
+	if len(body.Details) != 1 || body.Details[0].Field != "code" || body.Details[0].Message != "code must be a" {
+		t.Errorf("unexpected details from the first app: %+v", body.Details)
+	}
+}
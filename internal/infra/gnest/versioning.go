@@ -0,0 +1,359 @@
+package gnest
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ==========================================
+// 接口版本 (API Versioning)
+// ==========================================
+
+// 启用后路由按版本分发，版本通过元数据声明在路由组或路由上 (路由级优先)：
+//
+//	app.EnableVersioning(gnest.HeaderVersioning("X-API-Version"))
+//
+//	rg.UseMetadata(gnest.Version("1"))
+//	rg.POST("/login", ctrl.Login)                      // v1
+//	rg.POST("/login", ctrl.LoginV2, gnest.Version("2")) // v2
+//	rg.GET("/ping", ctrl.Ping, gnest.Version(gnest.VersionNeutral))
+//
+// 未声明版本的路由使用 DefaultVersion，DefaultVersion 为空时视为版本无关
+
+// VersionKey 路由版本的元数据键
+const VersionKey = "version"
+
+// VersionNeutral 版本无关：任何版本 (含未携带版本) 的请求都可命中
+const VersionNeutral = "*"
+
+const versionCtxKey = "gnest.version"
+
+// Version 声明路由 (组) 所属的版本，可同时属于多个版本
+func Version(versions ...string) Metadata {
+	return SetMetadata(VersionKey, versions)
+}
+
+type versioningKind int
+
+const (
+	uriVersioning versioningKind = iota
+	headerVersioning
+	mediaTypeVersioning
+)
+
+// VersioningStrategy 从请求中读取版本的方式
+type VersioningStrategy struct {
+	kind versioningKind
+	name string // URI 前缀 / 请求头名 / Accept 参数名
+}
+
+// URIVersioning 版本位于路径前缀，如 /v2/auth/login
+var URIVersioning = VersioningStrategy{kind: uriVersioning, name: "v"}
+
+// MediaTypeVersioning 版本位于 Accept 的参数中，如 Accept: application/json;v=2
+var MediaTypeVersioning = VersioningStrategy{kind: mediaTypeVersioning, name: "v"}
+
+// HeaderVersioning 版本位于请求头中，如 X-API-Version: 2
+func HeaderVersioning(name string) VersioningStrategy {
+	return VersioningStrategy{kind: headerVersioning, name: name}
+}
+
+// VersionFallback 请求的版本没有对应处理器时的策略
+type VersionFallback string
+
+const (
+	FallbackNone    VersionFallback = "none"    // 返回 404
+	FallbackDefault VersionFallback = "default" // 使用 DefaultVersion 的处理器
+	FallbackLatest  VersionFallback = "latest"  // 使用最新版本的处理器
+)
+
+// VersionDeprecation 已弃用版本的响应头信息
+type VersionDeprecation struct {
+	Since  time.Time // Deprecation 头，零值时输出 true
+	Sunset time.Time // Sunset 头，零值时不输出
+	Link   string    // 迁移说明，以 Link: <...>; rel="deprecation" 输出
+}
+
+// VersioningOptions 版本配置，未传给 EnableVersioning 时从容器中读取 *VersioningOptions
+type VersioningOptions struct {
+	DefaultVersion string // 未声明版本的路由与未携带版本的请求使用该版本
+	Fallback       VersionFallback
+	Deprecated     map[string]VersionDeprecation
+}
+
+var versioningOptionsType = reflect.TypeOf((*VersioningOptions)(nil))
+
+type versioning struct {
+	strategy  VersioningStrategy
+	opts      *VersioningOptions
+	pending   []versionedRoute              // Init 读取配置前声明的路由
+	endpoints map[string]*versionedEndpoint // method + 无版本路径
+}
+
+// versionedRoute Handle 中编译完成、等待按版本挂载的路由
+type versionedRoute struct {
+	method   string
+	path     string // 不含版本前缀的完整路径
+	versions []string
+	handler  gin.HandlerFunc
+	info     RouteInfo
+}
+
+// versionedEndpoint 同一路径下各版本的处理器，挂载在无版本路径上按请求分发
+type versionedEndpoint struct {
+	handlers  map[string]gin.HandlerFunc
+	neutral   gin.HandlerFunc
+	filters   *filterChain      // 未命中版本时的全局过滤器链
+	preparers []requestPreparer // 全局拦截器的请求准备 (如响应信封)
+}
+
+// EnableVersioning 启用接口版本，需在 Init 之前调用
+func (app *GnestApp) EnableVersioning(strategy VersioningStrategy, opts ...VersioningOptions) *GnestApp {
+	app.versioning = &versioning{strategy: strategy, endpoints: make(map[string]*versionedEndpoint)}
+	if len(opts) > 0 {
+		app.versioning.opts = &opts[0]
+	}
+	return app
+}
+
+// loadVersioning 在 Init 挂载控制器前读取配置，并挂载此前声明的路由
+func (app *GnestApp) loadVersioning() error {
+	v := app.versioning
+	if v == nil || v.opts != nil {
+		return nil
+	}
+	v.opts = &VersioningOptions{}
+	if def, ok := app.providers[token{typ: versioningOptionsType}]; ok {
+		rv, err := app.resolve(def, nil, nil)
+		if err != nil {
+			return err
+		}
+		if opts, _ := rv.Interface().(*VersioningOptions); opts != nil {
+			v.opts = opts
+		}
+	}
+	for _, r := range v.pending {
+		app.mountVersioned(r)
+	}
+	v.pending = nil
+	return nil
+}
+
+// addVersioned 由 Handle 调用，配置未读取时暂存
+func (app *GnestApp) addVersioned(r versionedRoute) {
+	if app.versioning.opts == nil {
+		app.versioning.pending = append(app.versioning.pending, r)
+		return
+	}
+	app.mountVersioned(r)
+}
+
+func (app *GnestApp) mountVersioned(r versionedRoute) {
+	v := app.versioning
+	versions := r.versions
+	if len(versions) == 0 {
+		versions = []string{VersionNeutral}
+		if v.opts.DefaultVersion != "" {
+			versions = []string{v.opts.DefaultVersion}
+		}
+	}
+
+	key := r.method + " " + r.path
+	ep := v.endpoints[key]
+	if ep == nil {
+		ep = &versionedEndpoint{
+			handlers: make(map[string]gin.HandlerFunc),
+			filters:  newFilterChain(concat(app.globalFilters, []ExceptionFilter{defaultFilter})),
+		}
+		for _, i := range app.overrideInterceptors(app.globalInterceptors) {
+			if p, ok := i.(requestPreparer); ok {
+				ep.preparers = append(ep.preparers, p)
+			}
+		}
+		v.endpoints[key] = ep
+		app.Engine.Handle(r.method, r.path, func(c *gin.Context) { app.dispatchVersion(c, ep) })
+	}
+
+	for _, ver := range versions {
+		info := r.info
+		info.Path = r.path
+		if ver == VersionNeutral {
+			if ep.neutral != nil {
+				app.errs = append(app.errs, fmt.Errorf("%s is declared twice as version neutral", key))
+				continue
+			}
+			ep.neutral = r.handler
+			app.routes = append(app.routes, info)
+			continue
+		}
+		if _, dup := ep.handlers[ver]; dup {
+			app.errs = append(app.errs, fmt.Errorf("%s is declared twice for version %s", key, ver))
+			continue
+		}
+		ep.handlers[ver] = r.handler
+		info.Version = ver
+		if v.strategy.kind == uriVersioning {
+			info.Path = joinPaths("/"+v.strategy.name+ver, r.path)
+			h := r.handler
+			ver := ver
+			app.Engine.Handle(r.method, info.Path, func(c *gin.Context) { app.serveVersion(c, ver, h) })
+		}
+		app.routes = append(app.routes, info)
+	}
+}
+
+// dispatchVersion 按请求中的版本选择处理器：
+// 未携带版本时依次尝试 DefaultVersion、版本无关的处理器；
+// 携带版本时依次尝试该版本、版本无关的处理器；仍未命中时按 Fallback 处理
+func (app *GnestApp) dispatchVersion(c *gin.Context, ep *versionedEndpoint) {
+	opts := app.versioning.opts
+	requested := app.versioning.requestVersion(c)
+
+	ver := requested
+	if ver == "" {
+		ver = opts.DefaultVersion
+	}
+	if h, ok := ep.handlers[ver]; ok {
+		app.serveVersion(c, ver, h)
+		return
+	}
+	if ep.neutral != nil {
+		app.serveVersion(c, requested, ep.neutral)
+		return
+	}
+	switch opts.Fallback {
+	case FallbackDefault:
+		if h, ok := ep.handlers[opts.DefaultVersion]; ok {
+			app.serveVersion(c, opts.DefaultVersion, h)
+			return
+		}
+	case FallbackLatest:
+		if latest := latestVersion(ep.handlers); latest != "" {
+			app.serveVersion(c, latest, ep.handlers[latest])
+			return
+		}
+	}
+	msg := "no handler for API version " + requested
+	if requested == "" {
+		msg = "API version is required"
+	}
+	// 未进入任何路由，与路由中的错误一样经过全局拦截器的请求准备与全局过滤器链
+	for _, p := range ep.preparers {
+		p.prepareRequest(c)
+	}
+	ep.filters.catch(c, NotFound(msg).WithCode("VERSION_NOT_FOUND"))
+}
+
+// serveVersion 记录命中的版本，已弃用时追加 Deprecation / Sunset 响应头
+func (app *GnestApp) serveVersion(c *gin.Context, ver string, h gin.HandlerFunc) {
+	c.Set(versionCtxKey, ver)
+	if d, ok := app.versioning.opts.Deprecated[ver]; ok {
+		if d.Since.IsZero() {
+			c.Header("Deprecation", "true")
+		} else {
+			c.Header("Deprecation", "@"+strconv.FormatInt(d.Since.Unix(), 10))
+		}
+		if !d.Sunset.IsZero() {
+			c.Header("Sunset", d.Sunset.UTC().Format(http.TimeFormat))
+		}
+		if d.Link != "" {
+			c.Writer.Header().Add("Link", "<"+d.Link+`>; rel="deprecation"`)
+		}
+	}
+	h(c)
+}
+
+// requestVersion 读取请求携带的版本，URI 策略下无版本前缀的请求返回空
+func (v *versioning) requestVersion(c *gin.Context) string {
+	switch v.strategy.kind {
+	case headerVersioning:
+		return strings.TrimPrefix(strings.TrimSpace(c.GetHeader(v.strategy.name)), "v")
+	case mediaTypeVersioning:
+		for _, part := range strings.Split(c.GetHeader("Accept"), ",") {
+			for _, param := range strings.Split(part, ";")[1:] {
+				k, val, _ := strings.Cut(strings.TrimSpace(param), "=")
+				if strings.EqualFold(k, v.strategy.name) {
+					return strings.Trim(val, `"`)
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// describe 在文档中说明如何请求该版本：请求头版本追加请求头参数，
+// 媒体类型版本 (OpenAPI 忽略名为 Accept 的参数) 在响应的媒体类型上附加版本参数
+func (v *versioning) describe(op *OpenAPIOperation, ver string) {
+	switch v.strategy.kind {
+	case headerVersioning:
+		required := v.opts == nil || v.opts.DefaultVersion != ver
+		op.Parameters = append(op.Parameters, &OpenAPIParameter{
+			Name: v.strategy.name, In: "header", Required: required,
+			Schema: &Schema{Type: "string", Enum: []interface{}{ver}},
+		})
+	case mediaTypeVersioning:
+		for _, resp := range op.Responses {
+			content := make(map[string]*OpenAPIMediaType, len(resp.Content))
+			for mt, c := range resp.Content {
+				content[mt+";"+v.strategy.name+"="+ver] = c
+			}
+			resp.Content = content
+		}
+	}
+}
+
+// VersionOf 当前请求命中的版本，未启用版本或命中版本无关的路由且请求未携带版本时为空
+func VersionOf(c *gin.Context) string {
+	return c.GetString(versionCtxKey)
+}
+
+// routeVersions 路由声明的版本，路由级覆盖组级
+func routeVersions(md *routeMetadata) []string {
+	if v, ok := md.route[VersionKey].([]string); ok {
+		return v
+	}
+	v, _ := md.group[VersionKey].([]string)
+	return v
+}
+
+// latestVersion 按 "." 分段比较，数字段按数值比较
+func latestVersion(handlers map[string]gin.HandlerFunc) string {
+	latest := ""
+	for ver := range handlers {
+		if latest == "" || compareVersions(ver, latest) > 0 {
+			latest = ver
+		}
+	}
+	return latest
+}
+
+func compareVersions(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		var x, y string
+		if i < len(as) {
+			x = as[i]
+		}
+		if i < len(bs) {
+			y = bs[i]
+		}
+		xn, errX := strconv.Atoi(x)
+		yn, errY := strconv.Atoi(y)
+		switch {
+		case errX == nil && errY == nil && xn != yn:
+			if xn > yn {
+				return 1
+			}
+			return -1
+		case (errX != nil || errY != nil) && x != y:
+			return strings.Compare(x, y)
+		}
+	}
+	return 0
+}
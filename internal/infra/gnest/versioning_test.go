@@ -0,0 +1,56 @@
+package gnest_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"blog/internal/infra/gnest"
+)
+
+// observingFilter 记录经过的错误码，不写出响应，交给后续过滤器处理
+type observingFilter struct{ codes *[]string }
+
+func (f observingFilter) Catch(c *gin.Context, err error) {
+	*f.codes = append(*f.codes, gnest.ToHttpException(err).Code)
+}
+
+func TestVersionNotFoundUsesGlobalFiltersAndEnvelope(t *testing.T) {
+	var codes []string
+	app := gnest.NewTestingApp()
+	app.EnableVersioning(gnest.HeaderVersioning("X-API-Version"), gnest.VersioningOptions{Fallback: gnest.FallbackNone})
+	app.UseGlobalFilters(observingFilter{&codes})
+	app.UseGlobalInterceptors(&gnest.EnvelopeInterceptor{})
+	app.GET("/users/me", func() string { return "v2" }, gnest.Version("2"))
+	if err := app.Compile(); err != nil {
+		t.Fatal(err)
+	}
+	client := app.Client(t)
+
+	cases := []struct {
+		name, version, message string
+	}{
+		{"missing version", "", "API version is required"},
+		{"unknown version", "3", "no handler for API version 3"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			codes = nil
+			body := client.GET("/users/me").Header("X-API-Version", tc.version).
+				ExpectStatus(http.StatusNotFound).ExpectErrorCode("VERSION_NOT_FOUND").Response().Body.String()
+			if want := `{"code":404,"message":"` + tc.message + `","data":{`; !strings.HasPrefix(body, want) {
+				t.Errorf("expected an enveloped error starting with %s, got %s", want, body)
+			}
+			if len(codes) != 1 || codes[0] != "VERSION_NOT_FOUND" {
+				t.Errorf("expected the global filter to see VERSION_NOT_FOUND, got %v", codes)
+			}
+		})
+	}
+
+	body := client.GET("/users/me").Header("X-API-Version", "2").ExpectStatus(http.StatusOK).Response().Body.String()
+	if body != `{"code":200,"message":"success","data":"v2"}` {
+		t.Errorf("unexpected versioned response: %s", body)
+	}
+}
@@ -2,6 +2,7 @@ package kafka
 
 import (
 	"context"
+	"errors"
 	"log"
 
 	"github.com/IBM/sarama"
@@ -9,8 +10,13 @@ import (
 
 type HandlerFunc func(msg *sarama.ConsumerMessage) error
 
+// ErrRedeliver 处理器返回的错误包装了它时不提交该消息的位点，并结束本轮消费，
+// 消息会在重新加入消费组后再次投递；其余错误只记录日志，位点照常提交
+var ErrRedeliver = errors.New("kafka: message left uncommitted for redelivery")
+
 type Consumer struct {
-	Group sarama.ConsumerGroup
+	Group  sarama.ConsumerGroup
+	cancel context.CancelFunc
 }
 
 func NewConsumer(brokers []string, group string) (*Consumer, error) {
@@ -33,14 +39,35 @@ func (c *Consumer) Consume(topics []string, fn HandlerFunc) {
 	}()
 
 	handler := groupHandler{fn: fn}
+	ctx, cancel := context.WithCancel(context.Background())
+	c.cancel = cancel
 
 	go func() {
-		for {
-			_ = c.Group.Consume(context.Background(), topics, handler)
+		for ctx.Err() == nil {
+			err := c.Group.Consume(ctx, topics, handler)
+			if errors.Is(err, sarama.ErrClosedConsumerGroup) {
+				return
+			}
 		}
 	}()
 }
 
+// Close 停止消费并退出消费组，触发分区再均衡
+func (c *Consumer) Close() error {
+	if c.cancel != nil {
+		c.cancel()
+	}
+	if err := c.Group.Close(); err != nil && !errors.Is(err, sarama.ErrClosedConsumerGroup) {
+		return err
+	}
+	return nil
+}
+
+// OnModuleDestroy 收到关闭信号后先停止消费，避免关闭期间继续拉取新消息
+func (c *Consumer) OnModuleDestroy(ctx context.Context) error {
+	return c.Close()
+}
+
 type groupHandler struct{ fn HandlerFunc }
 
 func (h groupHandler) Setup(sarama.ConsumerGroupSession) error   { return nil }
@@ -49,6 +76,9 @@ func (h groupHandler) ConsumeClaim(s sarama.ConsumerGroupSession, c sarama.Consu
 	for msg := range c.Messages() {
 		if err := h.fn(msg); err != nil {
 			log.Println("Handle message error:", err)
+			if errors.Is(err, ErrRedeliver) {
+				return err
+			}
 		}
 		s.MarkMessage(msg, "")
 	}
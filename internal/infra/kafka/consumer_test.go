@@ -0,0 +1,73 @@
+package kafka
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/IBM/sarama"
+)
+
+// fakeSession 记录被提交的位点，未实现的方法不会被 ConsumeClaim 调用
+type fakeSession struct {
+	sarama.ConsumerGroupSession
+	marked []int64
+}
+
+func (s *fakeSession) MarkMessage(msg *sarama.ConsumerMessage, _ string) {
+	s.marked = append(s.marked, msg.Offset)
+}
+
+type fakeClaim struct {
+	sarama.ConsumerGroupClaim
+	msgs chan *sarama.ConsumerMessage
+}
+
+func (c *fakeClaim) Messages() <-chan *sarama.ConsumerMessage { return c.msgs }
+
+func claimOf(offsets ...int64) *fakeClaim {
+	c := &fakeClaim{msgs: make(chan *sarama.ConsumerMessage, len(offsets))}
+	for _, o := range offsets {
+		c.msgs <- &sarama.ConsumerMessage{Topic: "post.published", Offset: o}
+	}
+	close(c.msgs)
+	return c
+}
+
+func TestConsumeClaimStopsOnRedeliver(t *testing.T) {
+	cases := []struct {
+		name       string
+		fail       map[int64]error
+		wantErr    bool
+		wantMarked []int64
+	}{
+		{name: "all handled", wantMarked: []int64{1, 2, 3}},
+		{name: "plain error is committed", fail: map[int64]error{2: errors.New("boom")}, wantMarked: []int64{1, 2, 3}},
+		{
+			name:       "redeliver leaves the offset uncommitted",
+			fail:       map[int64]error{2: fmt.Errorf("dlq down: %w", ErrRedeliver)},
+			wantErr:    true,
+			wantMarked: []int64{1},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var handled []int64
+			h := groupHandler{fn: func(msg *sarama.ConsumerMessage) error {
+				handled = append(handled, msg.Offset)
+				return tc.fail[msg.Offset]
+			}}
+			s := &fakeSession{}
+			err := h.ConsumeClaim(s, claimOf(1, 2, 3))
+			if tc.wantErr != errors.Is(err, ErrRedeliver) {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if fmt.Sprint(s.marked) != fmt.Sprint(tc.wantMarked) {
+				t.Errorf("expected marked offsets %v, got %v", tc.wantMarked, s.marked)
+			}
+			if tc.wantErr && len(handled) != 2 {
+				t.Errorf("expected consumption to stop after the redelivered message, handled %v", handled)
+			}
+		})
+	}
+}
@@ -6,7 +6,11 @@ import (
 	"github.com/IBM/sarama"
 )
 
-func SendDLQ(p *Producer, mainTopic string, msg *sarama.ConsumerMessage) {
+// SendDLQ 将消息原样投递到 <mainTopic>.dlq
+func SendDLQ(p *Producer, mainTopic string, msg *sarama.ConsumerMessage) error {
 	dlqTopic := fmt.Sprintf("%s.dlq", mainTopic)
-	_ = p.SendSync(dlqTopic, msg.Value)
+	if err := p.SendSync(dlqTopic, msg.Value); err != nil {
+		return fmt.Errorf("send to %s: %w", dlqTopic, err)
+	}
+	return nil
 }
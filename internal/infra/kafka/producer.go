@@ -1,6 +1,8 @@
 package kafka
 
 import (
+	"context"
+	"errors"
 	"log"
 	"time"
 
@@ -40,6 +42,16 @@ func NewProducer(brokers []string) (*Producer, error) {
 	return &Producer{Sync: sp, Async: ap}, nil
 }
 
+// Close 关闭生产者，异步生产者会先发送完缓冲中的消息
+func (p *Producer) Close() error {
+	return errors.Join(p.Async.Close(), p.Sync.Close())
+}
+
+// OnApplicationShutdown 在请求处理完毕后关闭，保证关闭期间产生的消息仍能发出
+func (p *Producer) OnApplicationShutdown(ctx context.Context, sig string) error {
+	return p.Close()
+}
+
 func (p *Producer) SendSync(topic string, data []byte) error {
 	_, _, err := p.Sync.SendMessage(&sarama.ProducerMessage{
 		Topic: topic,
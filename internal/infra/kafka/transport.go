@@ -0,0 +1,84 @@
+package kafka
+
+import (
+	"blog/internal/infra/gnest"
+	"context"
+	"errors"
+	"log"
+
+	"github.com/IBM/sarama"
+)
+
+// Transport 将消费组接入 gnest 的 MessagePattern 处理器，主题即消息模式：
+//
+//	app.ConnectMicroservice(kafka.NewTransport(consumer, producer), gnest.MicroserviceOptions{Decoder: kafka.DecodeJSON})
+//
+// 处理失败的消息通过 SendDLQ 投递到 <topic>.dlq，producer 为 nil 时只记录日志；
+// 投递死信失败时不提交位点，消息稍后重新消费
+type Transport struct {
+	Consumer *Consumer
+	Producer *Producer
+	cancel   context.CancelFunc
+	done     chan struct{}
+}
+
+func NewTransport(consumer *Consumer, producer *Producer) *Transport {
+	return &Transport{Consumer: consumer, Producer: producer}
+}
+
+// Listen 在后台消费所有消息模式对应的主题
+func (t *Transport) Listen(patterns []string, handle gnest.MessageHandler) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.cancel = cancel
+	t.done = make(chan struct{})
+
+	handler := groupHandler{fn: func(msg *sarama.ConsumerMessage) error {
+		err := handle(ctx, toMessage(msg))
+		if err == nil || t.Producer == nil {
+			return err
+		}
+		if dlqErr := SendDLQ(t.Producer, msg.Topic, msg); dlqErr != nil {
+			return errors.Join(ErrRedeliver, err, dlqErr)
+		}
+		return err
+	}}
+	go func() {
+		for err := range t.Consumer.Group.Errors() {
+			log.Println("Kafka Consumer Error:", err)
+		}
+	}()
+	go func() {
+		defer close(t.done)
+		for ctx.Err() == nil {
+			err := t.Consumer.Group.Consume(ctx, patterns, handler)
+			if errors.Is(err, sarama.ErrClosedConsumerGroup) {
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+// Close 停止消费，等待当前消息处理完毕后退出消费组
+func (t *Transport) Close() error {
+	if t.cancel == nil {
+		return nil
+	}
+	t.cancel()
+	<-t.done
+	return t.Consumer.Close()
+}
+
+func toMessage(msg *sarama.ConsumerMessage) *gnest.Message {
+	headers := make(map[string]string, len(msg.Headers))
+	for _, h := range msg.Headers {
+		headers[string(h.Key)] = string(h.Value)
+	}
+	return &gnest.Message{
+		Pattern: msg.Topic,
+		Key:     msg.Key,
+		Value:   msg.Value,
+		Headers: headers,
+		Raw:     msg,
+	}
+}
@@ -14,6 +14,21 @@ type LoggerService struct {
 	Log *zap.Logger
 }
 
+// LogLifecycleHook 记录 gnest 生命周期钩子的执行结果
+func (l *LoggerService) LogLifecycleHook(hook, provider string, timeout, elapsed time.Duration, err error) {
+	fields := []zap.Field{
+		zap.String("hook", hook),
+		zap.String("provider", provider),
+		zap.Duration("timeout", timeout),
+		zap.Duration("elapsed", elapsed),
+	}
+	if err != nil {
+		l.Log.Error("lifecycle hook failed", append(fields, zap.Error(err))...)
+		return
+	}
+	l.Log.Info("lifecycle hook", fields...)
+}
+
 func NewLoggerService(env string) *LoggerService {
 	logDir := "logs"
 	// 确保日志目录存在
@@ -1,6 +1,7 @@
 package pgsql
 
 import (
+	"context"
 	"fmt"
 	"time"
 
@@ -64,6 +65,20 @@ func NewPGSQL(cfg Config) (*PGSQL, error) {
 	return &PGSQL{DB: db}, nil
 }
 
+// OnModuleInit 启动时检查数据库连通性，失败则中止启动
+func (p *PGSQL) OnModuleInit(ctx context.Context) error {
+	sqlDB, err := p.DB.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.PingContext(ctx)
+}
+
+// OnApplicationShutdown HTTP 服务关闭后释放连接池
+func (p *PGSQL) OnApplicationShutdown(ctx context.Context, sig string) error {
+	return p.Close()
+}
+
 func (p *PGSQL) Close() error {
 	sqlDB, err := p.DB.DB()
 	if err != nil {
@@ -0,0 +1,74 @@
+// CacheStore 基于 Redis 的 gnest 缓存存储
+package redis
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	re "github.com/redis/go-redis/v9"
+)
+
+// cacheSetScript 写入缓存并登记到各标签集合，标签集合的过期时间取其下条目的最大值
+var cacheSetScript = re.NewScript(`
+local ttl = tonumber(ARGV[2])
+if ttl > 0 then
+	redis.call("SET", KEYS[1], ARGV[1], "PX", ttl)
+else
+	redis.call("SET", KEYS[1], ARGV[1])
+end
+for i = 2, #KEYS do
+	local existed = redis.call("EXISTS", KEYS[i]) == 1
+	local pttl = redis.call("PTTL", KEYS[i])
+	redis.call("SADD", KEYS[i], KEYS[1])
+	if ttl <= 0 then
+		redis.call("PERSIST", KEYS[i])
+	elseif not existed or (pttl >= 0 and pttl < ttl) then
+		redis.call("PEXPIRE", KEYS[i], ttl)
+	end
+end
+return 1
+`)
+
+// cacheInvalidateScript 删除标签集合及其登记的所有条目
+var cacheInvalidateScript = re.NewScript(`
+for i = 1, #KEYS do
+	for _, key in ipairs(redis.call("SMEMBERS", KEYS[i])) do
+		redis.call("DEL", key)
+	end
+	redis.call("DEL", KEYS[i])
+end
+return 1
+`)
+
+// CacheStore 实现 gnest.CacheStore：条目为普通键，标签为记录条目键的集合
+type CacheStore struct {
+	client *Client
+}
+
+func NewCacheStore(client *Client) *CacheStore {
+	return &CacheStore{client: client}
+}
+
+func (s *CacheStore) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	data, err := s.client.client.Get(ctx, key).Bytes()
+	if errors.Is(err, re.Nil) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return data, true, nil
+}
+
+func (s *CacheStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration, tags []string) error {
+	keys := append([]string{key}, tags...)
+	return cacheSetScript.Run(ctx, s.client.client, keys, value, ttl.Milliseconds()).Err()
+}
+
+func (s *CacheStore) InvalidateTags(ctx context.Context, tags ...string) error {
+	if len(tags) == 0 {
+		return nil
+	}
+	return cacheInvalidateScript.Run(ctx, s.client.client, tags).Err()
+}
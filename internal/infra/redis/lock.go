@@ -0,0 +1,30 @@
+// TryLock 基于 SET NX 的分布式锁
+package redis
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	re "github.com/redis/go-redis/v9"
+)
+
+// unlockScript 仅删除自己持有的锁，避免锁过期后误删其他实例的锁
+var unlockScript = re.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+func (r *Client) TryLock(ctx context.Context, key string, ttl time.Duration) (func(), bool, error) {
+	token := uuid.NewString()
+	ok, err := r.client.SetNX(ctx, key, token, ttl).Result()
+	if err != nil || !ok {
+		return nil, false, err
+	}
+	unlock := func() {
+		_ = unlockScript.Run(context.Background(), r.client, []string{key}, token).Err()
+	}
+	return unlock, true, nil
+}
@@ -38,3 +38,18 @@ func Default() *Client {
 func (r *Client) Ping(ctx context.Context) error {
 	return r.client.Ping(ctx).Err()
 }
+
+// Close 关闭连接池
+func (r *Client) Close() error {
+	return r.client.Close()
+}
+
+// OnModuleInit 启动时检查 Redis 连通性
+func (r *Client) OnModuleInit(ctx context.Context) error {
+	return r.Ping(ctx)
+}
+
+// OnApplicationShutdown HTTP 服务关闭后释放连接池
+func (r *Client) OnApplicationShutdown(ctx context.Context, sig string) error {
+	return r.Close()
+}
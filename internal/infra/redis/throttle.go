@@ -0,0 +1,119 @@
+// ThrottleStore 基于 Redis 的 gnest 限流存储，多实例共享额度
+package redis
+
+import (
+	"blog/internal/infra/gnest"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	re "github.com/redis/go-redis/v9"
+)
+
+// 脚本中的时间统一取 Redis 服务器时间，避免各实例时钟不一致；返回值均为毫秒
+
+// fixedWindowScript 返回 {count, pttl}
+var fixedWindowScript = re.NewScript(`
+local count = redis.call("INCR", KEYS[1])
+if count == 1 then
+	redis.call("PEXPIRE", KEYS[1], ARGV[1])
+end
+return {count, redis.call("PTTL", KEYS[1])}
+`)
+
+// slidingWindowScript 以有序集合记录窗口内的请求，返回 {allowed, count, reset}
+var slidingWindowScript = re.NewScript(`
+redis.replicate_commands()
+local t = redis.call("TIME")
+local now = tonumber(t[1]) * 1000 + math.floor(tonumber(t[2]) / 1000)
+local limit, window = tonumber(ARGV[1]), tonumber(ARGV[2])
+redis.call("ZREMRANGEBYSCORE", KEYS[1], "-inf", now - window)
+local count = redis.call("ZCARD", KEYS[1])
+local allowed = 0
+if count < limit then
+	redis.call("ZADD", KEYS[1], now, ARGV[3])
+	count = count + 1
+	allowed = 1
+end
+redis.call("PEXPIRE", KEYS[1], window)
+local reset = 0
+local oldest = redis.call("ZRANGE", KEYS[1], 0, 0, "WITHSCORES")
+if oldest[2] then
+	reset = tonumber(oldest[2]) + window - now
+end
+return {allowed, count, reset}
+`)
+
+// tokenBucketScript 以哈希记录令牌余量与补充时间，返回 {allowed, tokens, retryAfter, reset}
+var tokenBucketScript = re.NewScript(`
+redis.replicate_commands()
+local t = redis.call("TIME")
+local now = tonumber(t[1]) * 1000 + math.floor(tonumber(t[2]) / 1000)
+local capacity, window = tonumber(ARGV[1]), tonumber(ARGV[2])
+local rate = capacity / window
+local state = redis.call("HMGET", KEYS[1], "tokens", "ts")
+local tokens = tonumber(state[1]) or capacity
+local ts = tonumber(state[2]) or now
+tokens = math.min(capacity, tokens + (now - ts) * rate)
+local allowed, retry = 0, 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+else
+	retry = math.ceil((1 - tokens) / rate)
+end
+redis.call("HSET", KEYS[1], "tokens", tokens, "ts", now)
+redis.call("PEXPIRE", KEYS[1], window)
+return {allowed, math.floor(tokens), retry, math.ceil((capacity - tokens) / rate)}
+`)
+
+// ThrottleStore 实现 gnest.ThrottleStore，判断与扣减在同一个 Lua 脚本中原子完成
+type ThrottleStore struct {
+	client *Client
+}
+
+func NewThrottleStore(client *Client) *ThrottleStore {
+	return &ThrottleStore{client: client}
+}
+
+func (s *ThrottleStore) Take(ctx context.Context, key string, p gnest.ThrottlePolicy) (gnest.ThrottleResult, error) {
+	res := gnest.ThrottleResult{Limit: p.Limit}
+	window := p.Window.Milliseconds()
+	switch p.Algorithm {
+	case gnest.SlidingWindow:
+		v, err := slidingWindowScript.Run(ctx, s.client.client, []string{key}, p.Limit, window, uuid.NewString()).Int64Slice()
+		if err != nil {
+			return res, err
+		}
+		res.Allowed = v[0] == 1
+		res.Remaining = p.Limit - int(v[1])
+		res.Reset = time.Duration(v[2]) * time.Millisecond
+		if !res.Allowed {
+			res.RetryAfter = res.Reset
+		}
+	case gnest.TokenBucket:
+		v, err := tokenBucketScript.Run(ctx, s.client.client, []string{key}, p.Limit, window).Int64Slice()
+		if err != nil {
+			return res, err
+		}
+		res.Allowed = v[0] == 1
+		res.Remaining = int(v[1])
+		res.RetryAfter = time.Duration(v[2]) * time.Millisecond
+		res.Reset = time.Duration(v[3]) * time.Millisecond
+	case gnest.FixedWindow, "":
+		v, err := fixedWindowScript.Run(ctx, s.client.client, []string{key}, window).Int64Slice()
+		if err != nil {
+			return res, err
+		}
+		res.Allowed = v[0] <= int64(p.Limit)
+		res.Remaining = max(p.Limit-int(v[0]), 0)
+		res.Reset = time.Duration(v[1]) * time.Millisecond
+		if !res.Allowed {
+			res.RetryAfter = res.Reset
+		}
+	default:
+		return res, fmt.Errorf("redis: unsupported throttle algorithm %q", p.Algorithm)
+	}
+	return res, nil
+}
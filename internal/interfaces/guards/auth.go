@@ -0,0 +1,34 @@
+package guards
+
+import (
+	"blog/internal/common/constants"
+	"blog/internal/config"
+	"blog/internal/infra/gnest"
+	"blog/internal/interfaces/middlewares"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AuthGuard 鉴权守卫：校验请求头中的 accessToken，通过后将声明写入请求的 ctx，
+// 下游通过 gnest.UserFrom[*jwt.StandardClaims](ctx) 读取；Public() 标记的路由 (组) 跳过校验
+type AuthGuard struct {
+	// Gnest 依赖注入
+	Config    *gnest.ConfigService[config.Config]
+	Reflector *gnest.Reflector
+}
+
+func (g *AuthGuard) CanActivate(c *gin.Context) bool {
+	if g.Reflector.IsPublic(c) {
+		return true
+	}
+	token := c.GetHeader(constants.TOKEN_KEY)
+	if token == "" {
+		return gnest.Deny(c, gnest.Unauthorized("token must not be empty").WithCode("TOKEN_REQUIRED"))
+	}
+	claims, err := middlewares.VerifyToken(g.Config, token)
+	if err != nil {
+		return gnest.Deny(c, gnest.Unauthorized("invalid token").WithCode("TOKEN_INVALID").WithCause(err))
+	}
+	gnest.SetUser(c, claims)
+	return true
+}
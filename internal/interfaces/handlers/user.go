@@ -2,6 +2,8 @@ package handlers
 
 import (
 	"blog/internal/domain/user"
+	"blog/internal/infra/gnest"
+	"context"
 
 	"github.com/gin-gonic/gin"
 )
@@ -11,17 +13,47 @@ type UserController struct {
 	Svc *user.UserService
 }
 
-// gnest 会自动将 Body 绑定到 dto，并根据 DTO 里的 binding 标签校验
-func (ctrl *UserController) Register(dto *user.CreateUserDTO) interface{} {
-	u, err := ctrl.Svc.Register(dto)
+func (ctrl *UserController) Prefix() string { return "/auth" }
+
+func (ctrl *UserController) Routes(rg *gnest.RouterGroup) {
+	// 注册、登录与刷新 token 的接口无需鉴权，返回的是调用方自己的用户信息
+	rg.UseMetadata(gnest.Public(), gnest.SerializeGroups("self"))
+
+	// 注意：这里不需要再传 middlewares.Validate，gnest 内部已包含自动校验
+	rg.POST("/register", ctrl.Register, gnest.ApiOperation(gnest.Operation{
+		Summary:   "用户注册",
+		Responses: []gnest.ApiResponse{{Status: 200, Type: user.User{}}},
+	}))
+
+	rg.POST("/login", ctrl.Login, gnest.Throttle("login"), gnest.ApiOperation(gnest.Operation{
+		Summary:   "用户登录",
+		Responses: []gnest.ApiResponse{{Status: 200, Type: LoginResult{}}},
+	}))
+
+	rg.POST("/refresh-token", ctrl.RefreshToken, gnest.ApiOperation(gnest.Operation{
+		Summary: "刷新 accessToken",
+	}))
+}
+
+// LoginResult 登录接口的返回结构，仅用于生成接口文档
+type LoginResult struct {
+	User         *user.User `json:"user"`
+	AccessToken  string     `json:"accessToken"`
+	RefreshToken string     `json:"refreshToken"`
+}
+
+// gnest 会自动将 Body 绑定到 dto，并根据 DTO 里的 binding 标签校验；
+// ctx 为请求的上下文，携带请求 ID，客户端断开时取消数据库查询
+func (ctrl *UserController) Register(ctx context.Context, dto *user.CreateUserDTO) interface{} {
+	u, err := ctrl.Svc.Register(ctx, dto)
 	if err != nil {
 		return err // 返回 error 会被 gnest 过滤器捕获
 	}
 	return u
 }
 
-func (ctrl *UserController) Login(dto *user.CreateUserDTO) interface{} {
-	u, access, refresh, err := ctrl.Svc.Authenticate(dto.UserName, dto.Password)
+func (ctrl *UserController) Login(ctx context.Context, dto *user.CreateUserDTO) interface{} {
+	u, access, refresh, err := ctrl.Svc.Authenticate(ctx, dto.UserName, dto.Password)
 	if err != nil {
 		return err
 	}
@@ -32,8 +64,8 @@ func (ctrl *UserController) Login(dto *user.CreateUserDTO) interface{} {
 	}
 }
 
-func (ctrl *UserController) RefreshToken(dto *user.RefreshTokenDto) interface{} {
-	newAccess, err := ctrl.Svc.RefreshToken(dto.RefreshToken)
+func (ctrl *UserController) RefreshToken(ctx context.Context, dto *user.RefreshTokenDto) interface{} {
+	newAccess, err := ctrl.Svc.RefreshToken(ctx, dto.RefreshToken)
 	if err != nil {
 		return err
 	}
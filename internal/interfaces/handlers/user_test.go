@@ -0,0 +1,148 @@
+package handlers_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"blog/internal/config"
+	"blog/internal/domain/user"
+	"blog/internal/infra/gnest"
+	"blog/internal/interfaces/guards"
+	"blog/internal/router"
+
+	"github.com/dgrijalva/jwt-go"
+	"gorm.io/gorm"
+)
+
+// memoryRepository 内存中的 user.Repository，替换基于 Postgres 的实现
+type memoryRepository struct {
+	mu    sync.Mutex
+	users map[string]*user.User
+	err   error // 非 nil 时所有操作返回该错误，模拟数据库故障
+}
+
+func newMemoryRepository() *memoryRepository {
+	return &memoryRepository{users: make(map[string]*user.User)}
+}
+
+func (r *memoryRepository) Create(_ context.Context, u *user.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err != nil {
+		return r.err
+	}
+	r.users[u.UserName] = u
+	return nil
+}
+
+func (r *memoryRepository) FindByUserName(_ context.Context, userName string) (*user.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err != nil {
+		return nil, r.err
+	}
+	u, ok := r.users[userName]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return u, nil
+}
+
+func (r *memoryRepository) Update(_ context.Context, _ string, _ *user.User) error { return r.err }
+func (r *memoryRepository) Delete(_ context.Context, _ *user.User) error           { return r.err }
+
+// newAuthApp setup 可在编译前注册全局增强器或额外的路由
+func newAuthApp(t *testing.T, repo user.Repository, setup ...func(app *gnest.TestingApp)) *gnest.TestingApp {
+	t.Helper()
+	configModule := gnest.ConfigModule[config.Config](gnest.ConfigOptions{Dir: filepath.Join("..", "..", "config")})
+	app := gnest.NewTestingApp(router.AuthModule, configModule).
+		OverrideProvider((*user.Repository)(nil), repo)
+	for _, fn := range setup {
+		fn(app)
+	}
+	if err := app.Compile(); err != nil {
+		t.Fatal(err)
+	}
+	return app
+}
+
+func TestUserControllerRegisterAndLogin(t *testing.T) {
+	client := newAuthApp(t, newMemoryRepository()).Client(t)
+	dto := map[string]string{"userName": "alice", "password": "secret123", "email": "alice@example.com"}
+
+	var registered map[string]interface{}
+	client.POST("/auth/register").JSON(dto).ExpectStatus(http.StatusOK).DecodeInto(&registered)
+	if registered["userName"] != "alice" {
+		t.Errorf("unexpected register response: %v", registered)
+	}
+	for _, secret := range []string{"password", "salt", "refreshToken"} {
+		if _, ok := registered[secret]; ok {
+			t.Errorf("register response must not expose %s: %v", secret, registered)
+		}
+	}
+	client.POST("/auth/register").JSON(dto).ExpectStatus(http.StatusConflict).ExpectErrorCode("USERNAME_TAKEN")
+
+	var login struct {
+		AccessToken  string `json:"accessToken"`
+		RefreshToken string `json:"refreshToken"`
+	}
+	client.POST("/auth/login").JSON(dto).ExpectStatus(http.StatusOK).DecodeInto(&login)
+	if login.AccessToken == "" || login.RefreshToken == "" {
+		t.Errorf("expected tokens, got %+v", login)
+	}
+
+	wrong := map[string]string{"userName": "alice", "password": "wrong-password", "email": "alice@example.com"}
+	client.POST("/auth/login").JSON(wrong).ExpectStatus(http.StatusUnauthorized).ExpectErrorCode("PASSWORD_INCORRECT")
+	unknown := map[string]string{"userName": "bob", "password": "secret123", "email": "bob@example.com"}
+	client.POST("/auth/login").JSON(unknown).ExpectStatus(http.StatusUnauthorized).ExpectErrorCode("USER_NOT_REGISTERED")
+	client.POST("/auth/register").JSON(map[string]string{"userName": "carol"}).
+		ExpectStatus(http.StatusBadRequest).ExpectErrorCode(gnest.CodeValidationFailed)
+}
+
+func TestUserControllerLoginDatabaseFailure(t *testing.T) {
+	repo := newMemoryRepository()
+	repo.err = errors.New("pq: connection refused")
+	client := newAuthApp(t, repo).Client(t)
+
+	var body gnest.ErrorBody
+	client.POST("/auth/login").JSON(map[string]string{"userName": "alice", "password": "secret123", "email": "alice@example.com"}).
+		ExpectStatus(http.StatusInternalServerError).ExpectErrorCode(gnest.CodeInternalServerError).DecodeInto(&body)
+	if body.Message != "internal server error" {
+		t.Errorf("expected a generic message, got %q", body.Message)
+	}
+}
+
+func TestAuthGuardProvidesUser(t *testing.T) {
+	client := newAuthApp(t, newMemoryRepository(), func(app *gnest.TestingApp) {
+		auth := &guards.AuthGuard{}
+		app.Provide(auth)
+		app.UseGlobalGuards(auth)
+		app.GET("/me", func(ctx context.Context) string {
+			claims, _ := gnest.UserFrom[*jwt.StandardClaims](ctx)
+			if claims == nil {
+				return ""
+			}
+			return claims.Subject
+		})
+	}).Client(t)
+
+	// 注册与登录是 Public 路由，不需要 token
+	dto := map[string]string{"userName": "alice", "password": "secret123", "email": "alice@example.com"}
+	client.POST("/auth/register").JSON(dto).ExpectStatus(http.StatusOK)
+	var login struct {
+		AccessToken string `json:"accessToken"`
+	}
+	client.POST("/auth/login").JSON(dto).ExpectStatus(http.StatusOK).DecodeInto(&login)
+
+	subject := client.GET("/me").Header("Authorization", login.AccessToken).ExpectStatus(http.StatusOK).Response().Body.String()
+	if !strings.HasPrefix(subject, "alice") {
+		t.Errorf("expected the guard to provide alice's claims, got %q", subject)
+	}
+	client.GET("/me").ExpectStatus(http.StatusUnauthorized).ExpectErrorCode("TOKEN_REQUIRED")
+	client.GET("/me").Header("Authorization", "not-a-token").ExpectStatus(http.StatusUnauthorized).ExpectErrorCode("TOKEN_INVALID")
+}
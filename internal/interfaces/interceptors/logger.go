@@ -1,6 +1,7 @@
 package interceptors
 
 import (
+	"blog/internal/infra/gnest"
 	"blog/internal/infra/logger" // ！！！请替换为你的模块名
 	"bytes"
 	"encoding/json"
@@ -105,6 +106,7 @@ func (l *LoggingInterceptor) Intercept(c *gin.Context, next func() interface{})
 
 	// 8. 【文件 JSON 版写入】
 	fields := []zap.Field{
+		zap.String("requestId", gnest.RequestID(c.Request.Context())),
 		zap.String("url", c.Request.URL.String()),
 		zap.String("method", c.Request.Method),
 		zap.Int("code", c.Writer.Status()),
@@ -3,6 +3,7 @@ package middlewares
 import (
 	"blog/internal/common/constants"
 	"blog/internal/config"
+	"blog/internal/infra/gnest"
 	resp "blog/internal/pkg/response"
 	"errors"
 	"net/http"
@@ -11,25 +12,24 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-func verifyToken(tokenString string) (bool, error) {
-	cfg, err := config.LoadConfig()
-	if err != nil {
-		return false, err
-	}
+// VerifyToken 以配置中的 SecretKey 校验 accessToken 并返回其声明，AuthGuard 与 Auth 中间件共用
+func VerifyToken(cfg *gnest.ConfigService[config.Config], tokenString string) (*jwt.StandardClaims, error) {
+	secret := cfg.Get().SecretKey
 	token, err := jwt.ParseWithClaims(tokenString, &jwt.StandardClaims{}, func(token *jwt.Token) (interface{}, error) {
-		return []byte(cfg.SecretKey), nil
+		return []byte(secret), nil
 	})
 	if err != nil {
-		return false, err
+		return nil, err
 	}
-	_, ok := token.Claims.(*jwt.StandardClaims)
+	claims, ok := token.Claims.(*jwt.StandardClaims)
 	if !ok || !token.Valid {
-		return false, errors.New("token verification failed")
+		return nil, errors.New("token verification failed")
 	}
-	return true, nil
+	return claims, nil
 }
 
-func Auth() gin.HandlerFunc {
+// Auth 供直接挂在 gin 上的路由使用；gnest 路由请使用 guards.AuthGuard
+func Auth(cfg *gnest.ConfigService[config.Config]) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		token := c.GetHeader(constants.TOKEN_KEY)
 		if token == "" {
@@ -37,11 +37,13 @@ func Auth() gin.HandlerFunc {
 			return
 		}
 
-		isValidatedSuccess, err := verifyToken(token)
-		if err != nil || !isValidatedSuccess {
+		claims, err := VerifyToken(cfg, token)
+		if err != nil {
 			resp.SetCtxResponse(c, nil, http.StatusUnauthorized, err.Error())
 			return
 		}
+		// 当前用户写入请求的 ctx，下游通过 gnest.UserFrom[*jwt.StandardClaims](ctx) 读取
+		gnest.SetUser(c, claims)
 		c.Next()
 	}
 }
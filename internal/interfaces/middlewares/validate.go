@@ -1,57 +1,33 @@
 package middlewares
 
 import (
-	"fmt"
+	"errors"
 	"net/http"
-	"strings"
 
+	"blog/internal/infra/gnest"
 	resp "blog/internal/pkg/response"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
 )
 
-// validateParamsError 通用友好提示
-func validateParamsError(c *gin.Context, err error) bool {
-	if err == nil {
-		return false
-	}
-
-	// 尝试类型断言为 validator.ValidationErrors
-	validateErrors, ok := err.(validator.ValidationErrors)
-	if !ok {
-		return false
-	}
-
-	// 拼接所有字段的错误信息
-	var errMsgs []string
-	for _, e := range validateErrors {
-		// 使用 Field + Tag + Param 自动生成提示
-		msg := fmt.Sprintf("%s failed on '%s' validation", e.Field(), e.Tag())
-		if e.Param() != "" {
-			msg += fmt.Sprintf(" (param=%s)", e.Param())
-		}
-		errMsgs = append(errMsgs, msg)
-	}
-
-	// 返回 400
-	resp.SetCtxResponse(c, nil, http.StatusBadRequest, strings.Join(errMsgs, "; "))
-	c.Abort()
-	return true
-}
-
 // Validate 通用参数验证中间件
+//
+// Deprecated: gnest 在参数绑定阶段已自动校验，并按 Accept-Language 输出结构化的字段错误，
+// 直接在处理函数中声明 DTO 参数即可
 func Validate(dto interface{}) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// 绑定参数
-		err := c.ShouldBind(dto)
-		if isValidatedError := validateParamsError(c, err); isValidatedError {
-			return
-		}
-
-		// 其他绑定错误
-		if err != nil {
-			resp.SetCtxResponse(c, nil, http.StatusBadRequest, err.Error())
+		if err := c.ShouldBind(dto); err != nil {
+			var ve validator.ValidationErrors
+			if errors.As(err, &ve) {
+				// 校验错误：返回字段明细
+				he := gnest.ToHttpException(err)
+				resp.SetCtxResponse(c, he.Details, he.Status, he.Message)
+			} else {
+				// 其他绑定错误
+				resp.SetCtxResponse(c, nil, http.StatusBadRequest, err.Error())
+			}
 			c.Abort()
 			return
 		}
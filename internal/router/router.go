@@ -4,7 +4,7 @@ import (
 	"blog/internal/infra/gnest"
 )
 
-func Setup(app *gnest.GnestApp) {
-
-	setupAuthRouter(app)
+// Modules 汇总所有对外提供 HTTP 接口的模块，由 AppModule 统一导入
+var Modules = []*gnest.Module{
+	AuthModule,
 }
@@ -10,7 +10,7 @@ func main() {
 	aPort := port.FindAvailablePort(8089)
 	router, err := app.Setup()
 	if err != nil {
-		panic(fmt.Sprintf("service setup failed:", err.Error()))
+		panic(fmt.Sprintf("service setup failed: %s", err.Error()))
 	}
 	router.ListenAndServe(fmt.Sprintf("0.0.0.0:%d", aPort))
 	// if err != nil {